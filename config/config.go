@@ -3,18 +3,52 @@ package config
 import (
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Port   string `mapstructure:"PORT"`
-	DBConn string `mapstructure:"DB_CONN"`
+	Port           string        `mapstructure:"PORT"`
+	DBConn         string        `mapstructure:"DB_CONN"`
+	IdempotencyTTL time.Duration `mapstructure:"IDEMPOTENCY_TTL"`
+
+	// Keranjang
+	CartTTL             time.Duration `mapstructure:"CART_TTL"`              // keranjang yang tidak diubah selama ini dihapus
+	CartCleanupInterval time.Duration `mapstructure:"CART_CLEANUP_INTERVAL"` // jeda antar pembersihan keranjang kedaluwarsa
+
+	// Soft delete produk/kategori
+	PurgeRetention time.Duration `mapstructure:"PURGE_RETENTION"` // data yang dihapus lebih lama dari ini dihapus permanen
+	PurgeInterval  time.Duration `mapstructure:"PURGE_INTERVAL"`  // jeda antar purge; 0 menonaktifkan purge
+
+	// Stok menipis
+	LowStockVelocityDays   int           `mapstructure:"LOW_STOCK_VELOCITY_DAYS"`   // periode penjualan untuk menghitung velocity
+	LowStockCoverDays      int           `mapstructure:"LOW_STOCK_COVER_DAYS"`      // jumlah hari penjualan yang ditutup saran pesan ulang
+	StockAlertPollInterval time.Duration `mapstructure:"STOCK_ALERT_POLL_INTERVAL"` // jeda pengecekan alert baru untuk stream SSE
+
+	// Struk
+	StoreName           string `mapstructure:"STORE_NAME"`
+	StoreAddress        string `mapstructure:"STORE_ADDRESS"`
+	StorePhone          string `mapstructure:"STORE_PHONE"`
+	ReceiptFooter       string `mapstructure:"RECEIPT_FOOTER"`
+	ReceiptTemplatesDir string `mapstructure:"RECEIPT_TEMPLATES_DIR"` // override template bawaan, kosong = pakai bawaan
+	ReceiptPaperWidth   int    `mapstructure:"RECEIPT_PAPER_WIDTH"`   // lebar kertas thermal dalam mm (58 atau 80)
 }
 
 func Load() *Config {
 	viper.AutomaticEnv()
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("CART_TTL", "24h")
+	viper.SetDefault("CART_CLEANUP_INTERVAL", "10m")
+	viper.SetDefault("PURGE_RETENTION", "720h")
+	viper.SetDefault("PURGE_INTERVAL", "1h")
+	viper.SetDefault("LOW_STOCK_VELOCITY_DAYS", 30)
+	viper.SetDefault("LOW_STOCK_COVER_DAYS", 14)
+	viper.SetDefault("STOCK_ALERT_POLL_INTERVAL", "2s")
+	viper.SetDefault("STORE_NAME", "Simple CRUD Store")
+	viper.SetDefault("RECEIPT_FOOTER", "Terima kasih atas kunjungan Anda")
+	viper.SetDefault("RECEIPT_PAPER_WIDTH", 58)
 
 	if envFileExists(".env") {
 		viper.SetConfigFile(".env")
@@ -22,8 +56,26 @@ func Load() *Config {
 	}
 
 	return &Config{
-		Port:   viper.GetString("PORT"),
-		DBConn: viper.GetString("DB_CONN"),
+		Port:           viper.GetString("PORT"),
+		DBConn:         viper.GetString("DB_CONN"),
+		IdempotencyTTL: viper.GetDuration("IDEMPOTENCY_TTL"),
+
+		CartTTL:             viper.GetDuration("CART_TTL"),
+		CartCleanupInterval: viper.GetDuration("CART_CLEANUP_INTERVAL"),
+
+		PurgeRetention: viper.GetDuration("PURGE_RETENTION"),
+		PurgeInterval:  viper.GetDuration("PURGE_INTERVAL"),
+
+		LowStockVelocityDays:   viper.GetInt("LOW_STOCK_VELOCITY_DAYS"),
+		LowStockCoverDays:      viper.GetInt("LOW_STOCK_COVER_DAYS"),
+		StockAlertPollInterval: viper.GetDuration("STOCK_ALERT_POLL_INTERVAL"),
+
+		StoreName:           viper.GetString("STORE_NAME"),
+		StoreAddress:        viper.GetString("STORE_ADDRESS"),
+		StorePhone:          viper.GetString("STORE_PHONE"),
+		ReceiptFooter:       viper.GetString("RECEIPT_FOOTER"),
+		ReceiptTemplatesDir: viper.GetString("RECEIPT_TEMPLATES_DIR"),
+		ReceiptPaperWidth:   viper.GetInt("RECEIPT_PAPER_WIDTH"),
 	}
 }
 
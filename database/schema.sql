@@ -0,0 +1,424 @@
+-- Skema database PostgreSQL untuk simple-crud.
+-- Aman dijalankan berulang kali: psql "$DB_CONN" -f database/schema.sql
+
+CREATE TABLE IF NOT EXISTS categories (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS products (
+    id          SERIAL PRIMARY KEY,
+    category_id INT NOT NULL REFERENCES categories(id),
+    name        VARCHAR(255) NOT NULL,
+    price       NUMERIC(14,2) NOT NULL DEFAULT 0,
+    stock       INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id           SERIAL PRIMARY KEY,
+    total_amount NUMERIC(14,2) NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS transaction_details (
+    id             SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    product_id     INT REFERENCES products(id) ON DELETE SET NULL,
+    product_name   VARCHAR(255) NOT NULL DEFAULT '',
+    unit_price     NUMERIC(14,2) NOT NULL DEFAULT 0,
+    quantity       INT NOT NULL,
+    subtotal       NUMERIC(14,2) NOT NULL
+);
+
+-- Snapshot nama & harga produk per baris transaksi. Produk yang dihapus tidak lagi
+-- memblokir/merusak histori penjualan (product_id menjadi NULL).
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS product_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit_price NUMERIC(14,2) NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ALTER COLUMN product_id DROP NOT NULL;
+ALTER TABLE transaction_details
+    DROP CONSTRAINT IF EXISTS transaction_details_product_id_fkey,
+    ADD CONSTRAINT transaction_details_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE SET NULL;
+
+-- Backfill snapshot untuk baris lama
+UPDATE transaction_details td
+SET product_name = p.name,
+    unit_price = CASE WHEN td.quantity > 0 THEN td.subtotal / td.quantity ELSE p.price END
+FROM products p
+WHERE p.id = td.product_id AND td.product_name = '';
+
+-- Idempotency-Key untuk POST /api/v1/checkout
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key            VARCHAR(255) PRIMARY KEY,
+    request_hash   CHAR(64) NOT NULL,
+    transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+    response_body  JSONB,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at     TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+-- Void (pembatalan penuh di hari yang sama) dan refund parsial per baris transaksi
+CREATE TABLE IF NOT EXISTS refunds (
+    id             SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id),
+    type           VARCHAR(10) NOT NULL CHECK (type IN ('void', 'refund')),
+    total_amount   NUMERIC(14,2) NOT NULL,
+    reason         TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_transaction_id ON refunds (transaction_id);
+
+CREATE TABLE IF NOT EXISTS refund_details (
+    id                    SERIAL PRIMARY KEY,
+    refund_id             INT NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id),
+    product_id            INT NOT NULL,
+    quantity              INT NOT NULL CHECK (quantity > 0),
+    amount                NUMERIC(14,2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_details_transaction_detail_id ON refund_details (transaction_detail_id);
+
+-- Nilai uang disimpan sebagai NUMERIC(14,2) (fixed-point, 2 desimal) untuk database lama yang masih INT
+ALTER TABLE products ALTER COLUMN price TYPE NUMERIC(14,2);
+ALTER TABLE transactions ALTER COLUMN total_amount TYPE NUMERIC(14,2);
+ALTER TABLE transaction_details ALTER COLUMN unit_price TYPE NUMERIC(14,2);
+ALTER TABLE transaction_details ALTER COLUMN subtotal TYPE NUMERIC(14,2);
+ALTER TABLE refunds ALTER COLUMN total_amount TYPE NUMERIC(14,2);
+ALTER TABLE refund_details ALTER COLUMN amount TYPE NUMERIC(14,2);
+
+-- Promo & diskon yang dievaluasi saat checkout
+CREATE TABLE IF NOT EXISTS promotions (
+    id           SERIAL PRIMARY KEY,
+    name         VARCHAR(255) NOT NULL,
+    type         VARCHAR(20) NOT NULL CHECK (type IN ('percent', 'buy_x_get_y', 'cart_fixed')),
+    code         VARCHAR(50) NOT NULL DEFAULT '',
+    product_id   INT REFERENCES products(id) ON DELETE CASCADE,
+    category_id  INT REFERENCES categories(id) ON DELETE CASCADE,
+    percent_bp   INT NOT NULL DEFAULT 0,
+    buy_qty      INT NOT NULL DEFAULT 0,
+    get_qty      INT NOT NULL DEFAULT 0,
+    amount       NUMERIC(14,2) NOT NULL DEFAULT 0,
+    min_subtotal NUMERIC(14,2) NOT NULL DEFAULT 0,
+    stackable    BOOLEAN NOT NULL DEFAULT FALSE,
+    priority     INT NOT NULL DEFAULT 0,
+    active       BOOLEAN NOT NULL DEFAULT TRUE,
+    starts_at    TIMESTAMP,
+    ends_at      TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promotions_code ON promotions (code) WHERE code <> '';
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS discount_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS discount_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+-- Rincian diskon per baris transaksi (snapshot nama promo saat checkout)
+CREATE TABLE IF NOT EXISTS transaction_detail_discounts (
+    id                    SERIAL PRIMARY KEY,
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    promotion_id          INT REFERENCES promotions(id) ON DELETE SET NULL,
+    promotion_name        VARCHAR(255) NOT NULL,
+    amount                NUMERIC(14,2) NOT NULL
+);
+
+-- Tarif pajak (PPN) per kategori/produk, mode harga inclusive/exclusive
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id      SERIAL PRIMARY KEY,
+    name    VARCHAR(100) NOT NULL,
+    rate_bp INT NOT NULL CHECK (rate_bp BETWEEN 0 AND 10000),
+    mode    VARCHAR(10) NOT NULL CHECK (mode IN ('exclusive', 'inclusive')),
+    active  BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tax_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS tax_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS tax_rate_bp INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS tax_mode VARCHAR(10) NOT NULL DEFAULT '';
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS tax_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+-- Nilai yang dibayar untuk baris: subtotal - diskon (+ pajak jika exclusive)
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS total NUMERIC(14,2);
+UPDATE transaction_details SET total = subtotal - discount_amount WHERE total IS NULL;
+ALTER TABLE transaction_details ALTER COLUMN total SET NOT NULL;
+ALTER TABLE refund_details ADD COLUMN IF NOT EXISTS tax_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+-- Pembayaran (tender) per transaksi, mendukung split tender
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS amount_paid NUMERIC(14,2) NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS change_amount NUMERIC(14,2) NOT NULL DEFAULT 0;
+-- Transaksi lama dianggap dibayar pas
+UPDATE transactions SET amount_paid = total_amount WHERE amount_paid = 0 AND total_amount > 0;
+
+CREATE TABLE IF NOT EXISTS payments (
+    id             SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    method         VARCHAR(20) NOT NULL CHECK (method IN ('cash', 'qris', 'debit', 'credit')),
+    amount         NUMERIC(14,2) NOT NULL CHECK (amount > 0),
+    change_amount  NUMERIC(14,2) NOT NULL DEFAULT 0,
+    reference      VARCHAR(100) NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_transaction_id ON payments(transaction_id);
+
+-- Keranjang di sisi server (bisa di-hold/resume), dibersihkan otomatis setelah CART_TTL
+CREATE TABLE IF NOT EXISTS carts (
+    id             SERIAL PRIMARY KEY,
+    name           VARCHAR(100) NOT NULL DEFAULT '',
+    status         VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'held', 'checked_out')),
+    transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_carts_updated_at ON carts(updated_at);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id         SERIAL PRIMARY KEY,
+    cart_id    INT NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity   INT NOT NULL CHECK (quantity > 0),
+    UNIQUE (cart_id, product_id)
+);
+
+-- Ledger setiap perubahan products.stock
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id            SERIAL PRIMARY KEY,
+    product_id    INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    reason        VARCHAR(20) NOT NULL CHECK (reason IN ('sale', 'refund', 'adjustment', 'receiving', 'initial')),
+    quantity      INT NOT NULL,
+    balance_after INT NOT NULL,
+    reference_id  INT,
+    actor         VARCHAR(100) NOT NULL DEFAULT 'anonymous',
+    note          VARCHAR(255) NOT NULL DEFAULT '',
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements(product_id, created_at);
+
+-- Produk yang sudah ada sebelum ledger dibuat mendapat baris stok awal
+INSERT INTO stock_movements (product_id, reason, quantity, balance_after, actor, note)
+SELECT p.id, 'initial', p.stock, p.stock, 'system', 'saldo awal ledger'
+FROM products p
+WHERE p.stock <> 0
+  AND NOT EXISTS (SELECT 1 FROM stock_movements sm WHERE sm.product_id = p.id);
+
+-- Sesi stock-take (hitung stok fisik)
+CREATE TABLE IF NOT EXISTS stock_takes (
+    id           SERIAL PRIMARY KEY,
+    note         VARCHAR(255) NOT NULL DEFAULT '',
+    status       VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'committed', 'cancelled')),
+    created_by   VARCHAR(100) NOT NULL DEFAULT 'anonymous',
+    committed_by VARCHAR(100),
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at    TIMESTAMP
+);
+
+-- Hasil hitung per produk; system_quantity adalah stok sistem saat produk dihitung
+CREATE TABLE IF NOT EXISTS stock_take_items (
+    id               SERIAL PRIMARY KEY,
+    stock_take_id    INT NOT NULL REFERENCES stock_takes(id) ON DELETE CASCADE,
+    product_id       INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    counted_quantity INT NOT NULL CHECK (counted_quantity >= 0),
+    system_quantity  INT NOT NULL,
+    counted_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (stock_take_id, product_id)
+);
+
+-- Supplier dan purchase order
+CREATE TABLE IF NOT EXISTS suppliers (
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    phone      VARCHAR(50) NOT NULL DEFAULT '',
+    email      VARCHAR(100) NOT NULL DEFAULT '',
+    address    TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id          SERIAL PRIMARY KEY,
+    supplier_id INT NOT NULL REFERENCES suppliers(id),
+    status      VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'sent', 'partially_received', 'received', 'cancelled')),
+    note        VARCHAR(255) NOT NULL DEFAULT '',
+    created_by  VARCHAR(100) NOT NULL DEFAULT 'anonymous',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at     TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+
+-- product_name disalin agar PO tetap terbaca jika produk dihapus
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id                SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id        INT REFERENCES products(id) ON DELETE SET NULL,
+    product_name      VARCHAR(100) NOT NULL,
+    quantity_ordered  INT NOT NULL CHECK (quantity_ordered > 0),
+    quantity_received INT NOT NULL DEFAULT 0 CHECK (quantity_received >= 0 AND quantity_received <= quantity_ordered),
+    unit_cost         NUMERIC(14,2) NOT NULL DEFAULT 0
+);
+
+-- Setiap penerimaan barang (bisa sebagian) beserta harga pokok aktual per baris
+CREATE TABLE IF NOT EXISTS goods_receipts (
+    id                SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    note              VARCHAR(255) NOT NULL DEFAULT '',
+    received_by       VARCHAR(100) NOT NULL DEFAULT 'anonymous',
+    received_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS goods_receipt_items (
+    id                     SERIAL PRIMARY KEY,
+    goods_receipt_id       INT NOT NULL REFERENCES goods_receipts(id) ON DELETE CASCADE,
+    purchase_order_line_id INT NOT NULL REFERENCES purchase_order_lines(id) ON DELETE CASCADE,
+    quantity               INT NOT NULL CHECK (quantity > 0),
+    unit_cost              NUMERIC(14,2) NOT NULL DEFAULT 0
+);
+
+-- Harga pokok rata-rata tertimbang per produk dan snapshot-nya per baris transaksi
+ALTER TABLE products ADD COLUMN IF NOT EXISTS cost_price NUMERIC(14,2) NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit_cost NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+-- Batas stok minimum, quantity pesan ulang dan alert stok menipis dari checkout
+ALTER TABLE products ADD COLUMN IF NOT EXISTS min_stock INT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_qty INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS stock_alerts (
+    id             SERIAL PRIMARY KEY,
+    product_id     INT REFERENCES products(id) ON DELETE SET NULL,
+    product_name   VARCHAR(100) NOT NULL,
+    stock          INT NOT NULL,
+    min_stock      INT NOT NULL,
+    transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Index untuk filter dan keyset pagination daftar produk
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products(category_id);
+CREATE INDEX IF NOT EXISTS idx_products_name_id ON products(name, id);
+CREATE INDEX IF NOT EXISTS idx_products_price_id ON products(price, id);
+CREATE INDEX IF NOT EXISTS idx_products_stock_id ON products(stock, id);
+
+-- SKU unik dan barcode (EAN-8, UPC-A, EAN-13) produk; satu produk boleh punya beberapa barcode
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products(sku) WHERE sku IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS product_barcodes (
+    barcode    VARCHAR(13) PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_product_barcodes_product_id ON product_barcodes(product_id);
+
+-- Varian produk (mis. ukuran/warna). Tipe opsi dan nilainya per produk; setiap varian memiliki
+-- satu nilai untuk setiap tipe opsi. Stok produk bervarian = jumlah stok seluruh variannya.
+CREATE TABLE IF NOT EXISTS product_option_types (
+    id         SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name       VARCHAR(50) NOT NULL,
+    position   INT NOT NULL DEFAULT 0,
+    UNIQUE (product_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS product_option_values (
+    id             SERIAL PRIMARY KEY,
+    option_type_id INT NOT NULL REFERENCES product_option_types(id) ON DELETE CASCADE,
+    value          VARCHAR(50) NOT NULL,
+    position       INT NOT NULL DEFAULT 0,
+    UNIQUE (option_type_id, value)
+);
+
+CREATE TABLE IF NOT EXISTS product_variants (
+    id         SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku        VARCHAR(64),
+    price      NUMERIC(14,2) CHECK (price >= 0), -- NULL berarti memakai harga produk induk
+    stock      INT NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    option_key VARCHAR(255) NOT NULL,            -- id nilai opsi terurut, untuk mencegah kombinasi ganda
+    UNIQUE (product_id, option_key)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_sku ON product_variants(sku) WHERE sku IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS product_variant_options (
+    variant_id      INT NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    option_value_id INT NOT NULL REFERENCES product_option_values(id) ON DELETE CASCADE,
+    PRIMARY KEY (variant_id, option_value_id)
+);
+
+-- Snapshot varian per baris transaksi dan varian yang stoknya berubah di ledger
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS variant_id INT REFERENCES product_variants(id) ON DELETE SET NULL;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS variant_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS variant_id INT REFERENCES product_variants(id) ON DELETE SET NULL;
+
+-- Kategori bertingkat (mis. Minuman > Kopi > Kopi Botol). Siklus dicegah di aplikasi;
+-- kategori dengan sub-kategori tidak bisa dihapus tanpa memindahkan sub-kategorinya.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES categories(id);
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+-- Soft delete produk dan kategori. Baris dengan deleted_at disembunyikan dari daftar dan tidak bisa dijual,
+-- tetapi SKU/barcode-nya tetap dipesan sampai purge job menghapusnya permanen setelah masa retensi.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Optimistic concurrency: version naik setiap kali baris produk/kategori berubah (termasuk stok) dan dikirim
+-- sebagai ETag. PUT/PATCH/DELETE wajib membawa If-Match yang cocok dengan version saat ini.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+-- Metode pengembalian uang refund/void, dikurangkan dari rincian pembayaran per metode di laporan.
+-- Refund lama diisi dengan metode pembayaran terbesar transaksinya.
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS method VARCHAR(20);
+UPDATE refunds rf SET method = COALESCE((SELECT p.method FROM payments p WHERE p.transaction_id = rf.transaction_id
+    ORDER BY p.amount - p.change_amount DESC, p.id LIMIT 1), 'cash')
+WHERE rf.method IS NULL;
+ALTER TABLE refunds ALTER COLUMN method SET DEFAULT 'cash';
+ALTER TABLE refunds ALTER COLUMN method SET NOT NULL;
+
+-- Varian di keranjang, baris PO dan hasil stock-take. Baris tanpa varian memakai variant_id NULL dan
+-- variant_name kosong; varian yang dihapus menyisakan variant_id NULL dengan snapshot nama untuk riwayat.
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS variant_id INT REFERENCES product_variants(id) ON DELETE CASCADE;
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS cart_items_cart_id_product_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_product ON cart_items(cart_id, product_id) WHERE variant_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_items_variant ON cart_items(cart_id, variant_id) WHERE variant_id IS NOT NULL;
+
+ALTER TABLE purchase_order_lines ADD COLUMN IF NOT EXISTS variant_id INT REFERENCES product_variants(id) ON DELETE SET NULL;
+ALTER TABLE purchase_order_lines ADD COLUMN IF NOT EXISTS variant_name VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE stock_take_items ADD COLUMN IF NOT EXISTS variant_id INT REFERENCES product_variants(id) ON DELETE SET NULL;
+ALTER TABLE stock_take_items ADD COLUMN IF NOT EXISTS variant_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE stock_take_items DROP CONSTRAINT IF EXISTS stock_take_items_stock_take_id_product_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_take_items_product ON stock_take_items(stock_take_id, product_id)
+    WHERE variant_id IS NULL AND variant_name = '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_take_items_variant ON stock_take_items(stock_take_id, variant_id)
+    WHERE variant_id IS NOT NULL;
+
+-- Purge produk tidak menghapus histori stok: ledger dan hasil stock-take menyimpan snapshot nama produk,
+-- dan product_id-nya menjadi NULL saat produk dihapus permanen (sama seperti transaction_details).
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS product_name VARCHAR(255) NOT NULL DEFAULT '';
+UPDATE stock_movements sm SET product_name = p.name FROM products p WHERE p.id = sm.product_id AND sm.product_name = '';
+ALTER TABLE stock_movements ALTER COLUMN product_id DROP NOT NULL;
+ALTER TABLE stock_movements
+    DROP CONSTRAINT IF EXISTS stock_movements_product_id_fkey,
+    ADD CONSTRAINT stock_movements_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE SET NULL;
+
+ALTER TABLE stock_take_items ADD COLUMN IF NOT EXISTS product_name VARCHAR(255) NOT NULL DEFAULT '';
+UPDATE stock_take_items sti SET product_name = p.name FROM products p WHERE p.id = sti.product_id AND sti.product_name = '';
+ALTER TABLE stock_take_items ALTER COLUMN product_id DROP NOT NULL;
+ALTER TABLE stock_take_items
+    DROP CONSTRAINT IF EXISTS stock_take_items_product_id_fkey,
+    ADD CONSTRAINT stock_take_items_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE SET NULL;
+
+-- system_quantity wajib diisi; baris yang belum punya snapshot diisi dengan stok saat ini
+UPDATE stock_take_items sti
+SET system_quantity = COALESCE(
+    (SELECT v.stock FROM product_variants v WHERE v.id = sti.variant_id),
+    (SELECT p.stock FROM products p WHERE p.id = sti.product_id AND sti.variant_name = ''),
+    0)
+WHERE sti.system_quantity IS NULL;
+ALTER TABLE stock_take_items ALTER COLUMN system_quantity SET NOT NULL;
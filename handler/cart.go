@@ -0,0 +1,500 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"simple-crud/models"
+	"simple-crud/service"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CartHandler struct {
+	service service.CartService
+}
+
+func NewCartHandler(svc service.CartService) *CartHandler {
+	return &CartHandler{service: svc}
+}
+
+// ============================
+// GET ALL
+// ============================
+//
+// GetAll godoc
+// @Summary List carts
+// @Description List server-side carts with live price and stock, optionally filtered by status (e.g. status=held for parked orders)
+// @Tags carts
+// @Produce json
+// @Param status query string false "Cart status" Enums(open, held, checked_out)
+// @Success 200 {object} util.JSONResponse{data=[]models.Cart}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/carts [get]
+func (h *CartHandler) GetAll(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", models.CartStatusOpen, models.CartStatusHeld, models.CartStatusCheckedOut:
+	default:
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid status",
+			Data:    nil,
+		})
+		return
+	}
+
+	carts, err := h.service.GetAll(status)
+	if err != nil {
+		writeCartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "carts retrieved",
+		Data:    carts,
+	})
+}
+
+// ============================
+// GET BY ID
+// ============================
+//
+// GetByID godoc
+// @Summary Get cart by ID
+// @Description Get cart with items priced at the current product price and validated against current stock
+// @Tags carts
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Success 200 {object} util.JSONResponse{data=models.Cart}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Router /api/v1/carts/{id} [get]
+func (h *CartHandler) GetByID(c *gin.Context) {
+	id, ok := parseCartParam(c, "id")
+	if !ok {
+		return
+	}
+
+	cart, err := h.service.GetByID(id)
+	if err != nil {
+		writeCartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "cart retrieved",
+		Data:    cart,
+	})
+}
+
+// ============================
+// CREATE
+// ============================
+//
+// Create godoc
+// @Summary Create cart
+// @Description Create an empty open cart
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param cart body models.CreateCartRequest false "Cart payload"
+// @Success 201 {object} util.JSONResponse{data=models.Cart}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/carts [post]
+func (h *CartHandler) Create(c *gin.Context) {
+	var req models.CreateCartRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "Invalid request body",
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	cart, err := h.service.Create(req)
+	if err != nil {
+		writeCartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "cart created",
+		Data:    cart,
+	})
+}
+
+// ============================
+// DELETE
+// ============================
+//
+// Delete godoc
+// @Summary Delete cart
+// @Description Discard a cart and its items
+// @Tags carts
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Success 200 {object} util.JSONResponse
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Router /api/v1/carts/{id} [delete]
+func (h *CartHandler) Delete(c *gin.Context) {
+	id, ok := parseCartParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		writeCartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "cart deleted",
+		Data:    nil,
+	})
+}
+
+// ============================
+// ITEMS
+// ============================
+//
+// AddItem godoc
+// @Summary Add item to cart
+// @Description Add quantity of a product to an open cart. Products with variants require variant_id and are checked against the variant stock. Rejected when the cart quantity would exceed current stock.
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Param item body models.CartItemRequest true "Item payload"
+// @Success 200 {object} util.JSONResponse{data=models.Cart}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse{data=[]models.InsufficientStockItem}
+// @Router /api/v1/carts/{id}/items [post]
+func (h *CartHandler) AddItem(c *gin.Context) {
+	id, ok := parseCartParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.CartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+		return
+	}
+	if req.ProductID <= 0 || req.Quantity <= 0 || req.VariantID < 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "product_id dan quantity harus lebih dari 0",
+			Data:    nil,
+		})
+		return
+	}
+
+	cart, err := h.service.AddItem(id, req)
+	if err != nil {
+		writeCartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "item added",
+		Data:    cart,
+	})
+}
+
+// UpdateItem godoc
+// @Summary Update cart item quantity
+// @Description Replace the quantity of a product already in an open cart
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Param product_id path int true "Product ID"
+// @Param variant_id query int false "Variant ID, required for products with variants"
+// @Param item body models.CartItemRequest true "Item payload (only quantity is used)"
+// @Success 200 {object} util.JSONResponse{data=models.Cart}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse{data=[]models.InsufficientStockItem}
+// @Router /api/v1/carts/{id}/items/{product_id} [put]
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	id, ok := parseCartParam(c, "id")
+	if !ok {
+		return
+	}
+	productID, ok := parseCartParam(c, "product_id")
+	if !ok {
+		return
+	}
+	variantID, ok := parseCartVariant(c)
+	if !ok {
+		return
+	}
+
+	var req models.CartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+		return
+	}
+	if req.Quantity <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "quantity harus lebih dari 0, gunakan DELETE untuk menghapus item",
+			Data:    nil,
+		})
+		return
+	}
+
+	cart, err := h.service.UpdateItem(id, productID, variantID, req.Quantity)
+	if err != nil {
+		writeCartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "item updated",
+		Data:    cart,
+	})
+}
+
+// RemoveItem godoc
+// @Summary Remove item from cart
+// @Description Remove a product from an open cart
+// @Tags carts
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Param product_id path int true "Product ID"
+// @Param variant_id query int false "Variant ID, required for products with variants"
+// @Success 200 {object} util.JSONResponse{data=models.Cart}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Router /api/v1/carts/{id}/items/{product_id} [delete]
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	id, ok := parseCartParam(c, "id")
+	if !ok {
+		return
+	}
+	productID, ok := parseCartParam(c, "product_id")
+	if !ok {
+		return
+	}
+	variantID, ok := parseCartVariant(c)
+	if !ok {
+		return
+	}
+
+	cart, err := h.service.RemoveItem(id, productID, variantID)
+	if err != nil {
+		writeCartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "item removed",
+		Data:    cart,
+	})
+}
+
+// ============================
+// HOLD / RESUME
+// ============================
+//
+// Hold godoc
+// @Summary Hold (park) cart
+// @Description Park an open cart so the cashier can serve the next customer. Held carts cannot be changed until resumed.
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Param cart body models.HoldCartRequest false "Optional label, e.g. customer name"
+// @Success 200 {object} util.JSONResponse{data=models.Cart}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Router /api/v1/carts/{id}/hold [post]
+func (h *CartHandler) Hold(c *gin.Context) {
+	id, ok := parseCartParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.HoldCartRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "Invalid request body",
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	cart, err := h.service.Hold(id, req)
+	if err != nil {
+		writeCartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "cart held",
+		Data:    cart,
+	})
+}
+
+// Resume godoc
+// @Summary Resume held cart
+// @Description Reopen a held cart
+// @Tags carts
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Success 200 {object} util.JSONResponse{data=models.Cart}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Router /api/v1/carts/{id}/resume [post]
+func (h *CartHandler) Resume(c *gin.Context) {
+	id, ok := parseCartParam(c, "id")
+	if !ok {
+		return
+	}
+
+	cart, err := h.service.Resume(id)
+	if err != nil {
+		writeCartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "cart resumed",
+		Data:    cart,
+	})
+}
+
+// ============================
+// CHECKOUT
+// ============================
+//
+// Checkout godoc
+// @Summary Checkout cart
+// @Description Convert an open cart into a transaction using the same checkout rules as POST /api/v1/checkout (promotions, tax, stock lock, payments)
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param id path int true "Cart ID"
+// @Param checkout body models.CartCheckoutRequest false "Vouchers and payments"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 200 {object} util.JSONResponse{data=models.Transaction}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse{data=[]models.InsufficientStockItem}
+// @Failure 422 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/carts/{id}/checkout [post]
+func (h *CartHandler) Checkout(c *gin.Context) {
+	id, ok := parseCartParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.CartCheckoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "Invalid request body",
+				Data:    nil,
+			})
+			return
+		}
+	}
+	for _, payment := range req.Payments {
+		if err := payment.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	transaction, err := h.service.Checkout(id, req, actorFrom(c))
+	if err != nil {
+		writeCartError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "Checkout berhasil",
+		Data:    transaction,
+	})
+}
+
+func parseCartParam(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid " + name,
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// parseCartVariant membaca query variant_id opsional; kosong berarti item tanpa varian
+func parseCartVariant(c *gin.Context) (int, bool) {
+	raw := c.Query("variant_id")
+	if raw == "" {
+		return 0, true
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid variant_id",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// writeCartError memetakan error keranjang/checkout ke HTTP status
+func writeCartError(c *gin.Context, err error) {
+	var stockErr *models.ErrInsufficientStock
+	if errors.As(err, &stockErr) {
+		c.JSON(http.StatusConflict, util.JSONResponse{
+			Message: stockErr.Error(),
+			Data:    stockErr.Items,
+		})
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrCartNotFound), errors.Is(err, models.ErrCartItemNotFound), errors.Is(err, models.ErrProductNotFound),
+		errors.Is(err, models.ErrVariantNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrCartNotOpen), errors.Is(err, models.ErrCartNotHeld):
+		status = http.StatusConflict
+	case errors.Is(err, models.ErrCartEmpty), errors.Is(err, models.ErrVoucherNotApplicable),
+		errors.Is(err, models.ErrInsufficientPayment), errors.Is(err, models.ErrNonCashOverpayment),
+		errors.Is(err, models.ErrVariantRequired):
+		status = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(status, util.JSONResponse{
+		Message: err.Error(),
+		Data:    nil,
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -25,14 +26,25 @@ func NewCategoryHandler(svc service.CategoryService) *CategoryHandler {
 //
 // GetAll godoc
 // @Summary Get all categories
-// @Description Retrieve all categories
+// @Description Retrieve all categories. Soft-deleted categories are hidden unless include_deleted=true.
 // @Tags categories
 // @Produce json
+// @Param include_deleted query bool false "Also list soft-deleted categories (admin)"
 // @Success 200 {object} util.JSONResponse{data=[]model.Category}
+// @Failure 400 {object} util.JSONResponse
 // @Failure 500 {object} util.JSONResponse
 // @Router /api/v1/categories [get]
 func (h *CategoryHandler) GetAll(c *gin.Context) {
-	categories, err := h.service.GetAll()
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	categories, err := h.service.GetAll(includeDeleted)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, util.JSONResponse{
 			Message: err.Error(),
@@ -46,17 +58,57 @@ func (h *CategoryHandler) GetAll(c *gin.Context) {
 	})
 }
 
+// ============================
+// TREE
+// ============================
+//
+// GetTree godoc
+// @Summary Get category tree
+// @Description Retrieve all categories as a tree; every level is sorted by name. Soft-deleted categories are hidden unless include_deleted=true.
+// @Tags categories
+// @Produce json
+// @Param include_deleted query bool false "Also include soft-deleted categories (admin)"
+// @Success 200 {object} util.JSONResponse{data=[]model.CategoryNode}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/categories/tree [get]
+func (h *CategoryHandler) GetTree(c *gin.Context) {
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	tree, err := h.service.GetTree(includeDeleted)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "category tree retrieved",
+		Data:    tree,
+	})
+}
+
 // ============================
 // GET BY ID
 // ============================
 //
 // GetByID godoc
 // @Summary Get category by ID
-// @Description Get category detail by ID
+// @Description Get category detail by ID. Soft-deleted categories return 404 unless include_deleted=true.
 // @Tags categories
 // @Produce json
 // @Param id path int true "Category ID"
+// @Param include_deleted query bool false "Also return a soft-deleted category (admin)"
 // @Success 200 {object} util.JSONResponse{data=model.Category}
+// @Header 200 {string} ETag "Category version, send it back in If-Match"
 // @Failure 400 {object} util.JSONResponse
 // @Failure 404 {object} util.JSONResponse
 // @Router /api/v1/categories/{id} [get]
@@ -71,7 +123,16 @@ func (h *CategoryHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	category, err := h.service.GetByID(id)
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	category, err := h.service.GetByID(id, includeDeleted)
 	if err != nil {
 		c.JSON(http.StatusNotFound, util.JSONResponse{
 			Message: err.Error(),
@@ -80,6 +141,7 @@ func (h *CategoryHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", model.ETag(category.Version))
 	c.JSON(http.StatusOK, util.JSONResponse{
 		Message: "category retrieved",
 		Data:    category,
@@ -92,12 +154,13 @@ func (h *CategoryHandler) GetByID(c *gin.Context) {
 //
 // Create godoc
 // @Summary Create new category
-// @Description Create a new category
+// @Description Create a new category, optionally under a parent category (parent_id)
 // @Tags categories
 // @Accept json
 // @Produce json
 // @Param category body model.Category true "Category payload"
 // @Success 201 {object} util.JSONResponse{data=model.Category}
+// @Header 201 {string} ETag "Category version"
 // @Failure 400 {object} util.JSONResponse
 // @Router /api/v1/categories [post]
 func (h *CategoryHandler) Create(c *gin.Context) {
@@ -112,13 +175,14 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 
 	created, err := h.service.Create(payload)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, util.JSONResponse{
+		c.JSON(categoryErrorStatus(err, http.StatusBadRequest), util.JSONResponse{
 			Message: err.Error(),
 			Data:    nil,
 		})
 		return
 	}
 
+	c.Header("ETag", model.ETag(created.Version))
 	c.JSON(http.StatusCreated, util.JSONResponse{
 		Message: "category created",
 		Data:    created,
@@ -131,14 +195,19 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 //
 // Update godoc
 // @Summary Update category
-// @Description Update category by ID
+// @Description Update category by ID. parent_id may not point to the category itself or one of its descendants. If-Match must carry the ETag of the version being replaced.
 // @Tags categories
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
+// @Param If-Match header string true "ETag returned by GET or the previous write"
 // @Param category body model.Category true "Category payload"
 // @Success 200 {object} util.JSONResponse
+// @Header 200 {string} ETag "New category version"
 // @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 412 {object} util.JSONResponse
+// @Failure 428 {object} util.JSONResponse
 // @Router /api/v1/categories/{id} [put]
 func (h *CategoryHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
@@ -160,19 +229,93 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Update(id, payload); err != nil {
+	ifMatch, ok := ifMatchFrom(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.service.Update(id, payload, ifMatch)
+	if err != nil {
+		if writeVersionMismatch(c, err) {
+			return
+		}
+		c.JSON(categoryErrorStatus(err, http.StatusBadRequest), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.Header("ETag", model.ETag(category.Version))
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "category updated",
+		Data: gin.H{
+			"id":       id,
+			"category": category,
+		},
+	})
+}
+
+// ============================
+// PATCH
+// ============================
+//
+// Patch godoc
+// @Summary Partially update category
+// @Description Apply a JSON Merge Patch (RFC 7396) to a category. Only the supplied fields change and null clears a field, e.g. parent_id null moves the category to the top level. The merged category is validated like a full update. If-Match must carry the ETag of the version being patched.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param If-Match header string true "ETag returned by GET or the previous write"
+// @Param patch body object true "Merge patch with only the fields to change"
+// @Success 200 {object} util.JSONResponse
+// @Header 200 {string} ETag "New category version"
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 412 {object} util.JSONResponse
+// @Failure 415 {object} util.JSONResponse
+// @Failure 428 {object} util.JSONResponse
+// @Router /api/v1/categories/{id} [patch]
+func (h *CategoryHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
 		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return
+	}
+
+	patch, ok := readMergePatch(c)
+	if !ok {
+		return
+	}
+
+	ifMatch, ok := ifMatchFrom(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.service.Patch(id, patch, ifMatch)
+	if err != nil {
+		if writeVersionMismatch(c, err) {
+			return
+		}
+		c.JSON(categoryErrorStatus(err, http.StatusBadRequest), util.JSONResponse{
 			Message: err.Error(),
 			Data:    nil,
 		})
 		return
 	}
 
+	c.Header("ETag", model.ETag(category.Version))
 	c.JSON(http.StatusOK, util.JSONResponse{
 		Message: "category updated",
 		Data: gin.H{
 			"id":       id,
-			"category": payload,
+			"category": category,
 		},
 	})
 }
@@ -183,12 +326,20 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 //
 // Delete godoc
 // @Summary Delete category
-// @Description Delete category by ID
+// @Description Soft-delete category by ID. A category with active sub-categories is refused unless children=reparent, which moves them to the deleted category's parent. A category still used by active products is refused with the product count unless reassign_to is given, which moves those products to that category in the same transaction.
 // @Tags categories
 // @Produce json
 // @Param id path int true "Category ID"
-// @Success 200 {object} util.JSONResponse
+// @Param children query string false "Sub-category handling (default refuse)" Enums(refuse, reparent)
+// @Param reassign_to query int false "Move products of this category to this category ID before deleting"
+// @Param If-Match header string true "ETag returned by GET or the previous write"
+// @Success 200 {object} util.JSONResponse{data=model.CategoryDeleteResult}
 // @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse{data=model.CategoryUsage}
+// @Failure 412 {object} util.JSONResponse
+// @Failure 428 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
 // @Router /api/v1/categories/{id} [delete]
 func (h *CategoryHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
@@ -201,8 +352,38 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
-		c.JSON(http.StatusBadRequest, util.JSONResponse{
+	opts := model.CategoryDeleteOptions{Children: c.Query("children")}
+	if v := c.Query("reassign_to"); v != "" {
+		target, err := strconv.Atoi(v)
+		if err != nil || target <= 0 {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "invalid reassign_to",
+				Data:    nil,
+			})
+			return
+		}
+		opts.ReassignTo = &target
+	}
+
+	ifMatch, ok := ifMatchFrom(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Delete(id, opts, ifMatch)
+	if err != nil {
+		if writeVersionMismatch(c, err) {
+			return
+		}
+		var inUse *model.ErrCategoryInUse
+		if errors.As(err, &inUse) {
+			c.JSON(http.StatusConflict, util.JSONResponse{
+				Message: inUse.Error(),
+				Data:    inUse.Usage,
+			})
+			return
+		}
+		c.JSON(categoryErrorStatus(err, http.StatusBadRequest), util.JSONResponse{
 			Message: err.Error(),
 			Data:    nil,
 		})
@@ -211,8 +392,67 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, util.JSONResponse{
 		Message: "category deleted",
-		Data: gin.H{
-			"id": id,
-		},
+		Data:    result,
 	})
 }
+
+// ============================
+// RESTORE
+// ============================
+//
+// Restore godoc
+// @Summary Restore category
+// @Description Restore a soft-deleted category. Its parent must not be deleted; restore the parent first. Products and sub-categories deleted separately stay deleted.
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} util.JSONResponse{data=model.Category}
+// @Header 200 {string} ETag "New category version"
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/categories/{id}/restore [post]
+func (h *CategoryHandler) Restore(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return
+	}
+
+	category, err := h.service.Restore(id)
+	if err != nil {
+		c.JSON(categoryErrorStatus(err, http.StatusBadRequest), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.Header("ETag", model.ETag(category.Version))
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "category restored",
+		Data:    category,
+	})
+}
+
+// categoryErrorStatus memetakan error kategori ke HTTP status; error lain memakai fallback
+func categoryErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, model.ErrCategoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, model.ErrCategoryHasChildren), errors.Is(err, model.ErrCategoryNotDeleted),
+		errors.Is(err, model.ErrCategoryDeleted):
+		return http.StatusConflict
+	case errors.Is(err, model.ErrInvalidCategoryParent), errors.Is(err, model.ErrInvalidCategoryDeleteMode),
+		errors.Is(err, model.ErrInvalidCategoryReassign), errors.Is(err, model.ErrInvalidCategory),
+		errors.Is(err, util.ErrInvalidMergePatch):
+		return http.StatusBadRequest
+	default:
+		return fallback
+	}
+}
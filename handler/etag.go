@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"simple-crud/models"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ifMatchFrom membaca header If-Match yang wajib untuk PUT/PATCH/DELETE produk dan kategori.
+// Header kosong dijawab 428, format yang tidak bisa dibaca dijawab 400.
+func ifMatchFrom(c *gin.Context) (models.IfMatch, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, util.JSONResponse{
+			Message: models.ErrPreconditionRequired.Error(),
+			Data:    nil,
+		})
+		return models.IfMatch{}, false
+	}
+	if header == "*" {
+		return models.IfMatch{Any: true}, true
+	}
+
+	var m models.IfMatch
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		// Weak ETag tidak pernah cocok karena If-Match memakai perbandingan strong
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: `invalid If-Match, gunakan ETag dari response sebelumnya, mis. "3"`,
+				Data:    nil,
+			})
+			return models.IfMatch{}, false
+		}
+		if v, err := strconv.Atoi(tag[1 : len(tag)-1]); err == nil {
+			m.Versions = append(m.Versions, v)
+		}
+	}
+	return m, true
+}
+
+// writeVersionMismatch menjawab 412 beserta ETag versi saat ini jika err adalah ErrVersionMismatch
+func writeVersionMismatch(c *gin.Context, err error) bool {
+	var mismatch *models.ErrVersionMismatch
+	if !errors.As(err, &mismatch) {
+		return false
+	}
+	c.Header("ETag", models.ETag(mismatch.Current))
+	c.JSON(http.StatusPreconditionFailed, util.JSONResponse{
+		Message: mismatch.Error(),
+		Data:    nil,
+	})
+	return true
+}
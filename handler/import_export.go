@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"simple-crud/models"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize membatasi ukuran file upload import
+const maxImportFileSize = 20 << 20
+
+var spreadsheetContentTypes = map[string]string{
+	models.SpreadsheetFormatCSV:  "text/csv; charset=utf-8",
+	models.SpreadsheetFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// openImportFile membuka file upload (field "file") dan menentukan formatnya dari query format atau ekstensi file
+func openImportFile(c *gin.Context) (io.ReadCloser, string, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "file wajib diupload sebagai multipart field \"file\" (maksimal 20MB)",
+			Data:    nil,
+		})
+		return nil, "", false
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+	if _, ok := spreadsheetContentTypes[format]; !ok {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: models.ErrUnsupportedSpreadsheetFormat.Error(),
+			Data:    nil,
+		})
+		return nil, "", false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return nil, "", false
+	}
+	return file, format, true
+}
+
+// writeImportReport mengirim report import: 200 jika dry_run atau commit berhasil, 422 jika commit ditolak karena ada baris gagal
+func writeImportReport(c *gin.Context, report *models.ImportReport, err error) {
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrInvalidImport) || errors.Is(err, models.ErrInvalidImportMode) ||
+			errors.Is(err, models.ErrUnsupportedSpreadsheetFormat) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	switch {
+	case report.Mode == models.ImportModeDryRun:
+		c.JSON(http.StatusOK, util.JSONResponse{
+			Message: "dry run selesai, tidak ada data yang disimpan",
+			Data:    report,
+		})
+	case !report.Committed:
+		c.JSON(http.StatusUnprocessableEntity, util.JSONResponse{
+			Message: "import dibatalkan karena ada baris yang gagal validasi",
+			Data:    report,
+		})
+	default:
+		c.JSON(http.StatusOK, util.JSONResponse{
+			Message: "import berhasil",
+			Data:    report,
+		})
+	}
+}
+
+// startSpreadsheetDownload memvalidasi format export lalu menulis header download.
+// Setelah ini body langsung di-stream sehingga error di tengah jalan hanya bisa di-log.
+func startSpreadsheetDownload(c *gin.Context, name string) (string, bool) {
+	format := c.DefaultQuery("format", models.SpreadsheetFormatCSV)
+	contentType, ok := spreadsheetContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: models.ErrUnsupportedSpreadsheetFormat.Error(),
+			Data:    nil,
+		})
+		return "", false
+	}
+
+	filename := name + "-" + time.Now().Format("20060102") + "." + format
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Status(http.StatusOK)
+	return format, true
+}
+
+// ============================
+// PRODUCT IMPORT / EXPORT
+// ============================
+//
+// Import godoc
+// @Summary Import products from CSV/XLSX
+// @Description Upsert products by SKU from a spreadsheet. Columns: sku (required), name, category_id or category (name), price, stock, cost_price, min_stock, reorder_qty, tax_rate_id, barcodes (separated by ;). Empty cells keep the existing value. dry_run validates everything against the database without saving; commit saves all rows in one transaction only if every row is valid.
+// @Tags products
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Param mode query string false "Import mode (default dry_run)" Enums(dry_run, commit)
+// @Param format query string false "File format, defaults to the file extension" Enums(csv, xlsx)
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 200 {object} util.JSONResponse{data=models.ImportReport}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 422 {object} util.JSONResponse{data=models.ImportReport}
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/import [post]
+func (h *ProductHandler) Import(c *gin.Context) {
+	file, format, ok := openImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	report, err := h.service.Import(file, format, c.DefaultQuery("mode", models.ImportModeDryRun), actorFrom(c))
+	writeImportReport(c, report, err)
+}
+
+// Export godoc
+// @Summary Export products to CSV/XLSX
+// @Description Stream the current product catalog with the same columns accepted by the import endpoint
+// @Tags products
+// @Produce octet-stream
+// @Param format query string false "File format (default csv)" Enums(csv, xlsx)
+// @Success 200 {file} file
+// @Failure 400 {object} util.JSONResponse
+// @Router /api/v1/products/export [get]
+func (h *ProductHandler) Export(c *gin.Context) {
+	format, ok := startSpreadsheetDownload(c, "products")
+	if !ok {
+		return
+	}
+	if err := h.service.Export(c.Writer, format); err != nil {
+		log.Println("product export failed:", err)
+		c.Abort()
+	}
+}
+
+// ============================
+// CATEGORY IMPORT / EXPORT
+// ============================
+//
+// Import godoc
+// @Summary Import categories from CSV/XLSX
+// @Description Upsert categories from a spreadsheet. Columns: id, name, description, tax_rate_id. Rows with id update that category, rows without id are matched by name (case-insensitive) or created. Modes behave like the product import.
+// @Tags categories
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Param mode query string false "Import mode (default dry_run)" Enums(dry_run, commit)
+// @Param format query string false "File format, defaults to the file extension" Enums(csv, xlsx)
+// @Success 200 {object} util.JSONResponse{data=models.ImportReport}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 422 {object} util.JSONResponse{data=models.ImportReport}
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/categories/import [post]
+func (h *CategoryHandler) Import(c *gin.Context) {
+	file, format, ok := openImportFile(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	report, err := h.service.Import(file, format, c.DefaultQuery("mode", models.ImportModeDryRun))
+	writeImportReport(c, report, err)
+}
+
+// Export godoc
+// @Summary Export categories to CSV/XLSX
+// @Description Download all categories with the same columns accepted by the import endpoint
+// @Tags categories
+// @Produce octet-stream
+// @Param format query string false "File format (default csv)" Enums(csv, xlsx)
+// @Success 200 {file} file
+// @Failure 400 {object} util.JSONResponse
+// @Router /api/v1/categories/export [get]
+func (h *CategoryHandler) Export(c *gin.Context) {
+	format, ok := startSpreadsheetDownload(c, "categories")
+	if !ok {
+		return
+	}
+	if err := h.service.Export(c.Writer, format); err != nil {
+		log.Println("category export failed:", err)
+		c.Abort()
+	}
+}
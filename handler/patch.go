@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mergePatchContentType adalah media type JSON Merge Patch (RFC 7396)
+const mergePatchContentType = "application/merge-patch+json"
+
+// readMergePatch membaca body PATCH; hanya application/merge-patch+json dan application/json yang diterima
+func readMergePatch(c *gin.Context) ([]byte, bool) {
+	mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil || (mediaType != mergePatchContentType && mediaType != "application/json") {
+		c.JSON(http.StatusUnsupportedMediaType, util.JSONResponse{
+			Message: "Content-Type harus " + mergePatchContentType + " atau application/json",
+			Data:    nil,
+		})
+		return nil, false
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return nil, false
+	}
+	return body, true
+}
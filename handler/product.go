@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	model "simple-crud/models"
 	"simple-crud/service"
@@ -28,16 +30,37 @@ func NewProductHandler(svc service.ProductService) *ProductHandler {
 //
 // GetAll godoc
 // @Summary Get all products
-// @Description Get list of products with category
+// @Description Get list of products with category. Supports filtering, sorting and either offset (page) or cursor pagination. meta contains the total count plus next/prev links and cursors.
 // @Tags products
 // @Produce json
+// @Param name query string false "Filter by name (case-insensitive, partial match)"
+// @Param category_id query int false "Filter by category ID, including all of its sub-categories"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param in_stock query bool false "true: only products in stock, false: only out of stock"
+// @Param stock_min query int false "Minimum stock"
+// @Param stock_max query int false "Maximum stock"
+// @Param sort query string false "Sort field (default id)" Enums(id, name, price, stock)
+// @Param order query string false "Sort order (default asc)" Enums(asc, desc)
+// @Param page query int false "Page for offset pagination (default 1)"
+// @Param limit query int false "Items per page (default 50, max 200)"
+// @Param cursor query string false "Cursor from meta.next_cursor or meta.prev_cursor; cannot be combined with page"
+// @Param include_deleted query bool false "Also list soft-deleted products (admin)"
 // @Success 200 {object} util.JSONResponse{data=[]util.ProductResp}
+// @Failure 400 {object} util.JSONResponse
 // @Failure 500 {object} util.JSONResponse
 // @Router /api/v1/products [get]
 func (h *ProductHandler) GetAll(c *gin.Context) {
-	name := c.Request.URL.Query().Get("name")
-	fmt.Println(name)
-	products, err := h.service.GetAll(name)
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	page, err := h.service.GetAll(filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, util.JSONResponse{
 			Message: "Internal Server Error",
@@ -46,37 +69,173 @@ func (h *ProductHandler) GetAll(c *gin.Context) {
 		return
 	}
 
-	resp := make([]util.ProductResp, 0, len(products))
-	for _, p := range products {
-		resp = append(resp, util.ProductResp{
-			ID:    p.ID,
-			Name:  p.Name,
-			Price: p.Price,
-			Stock: p.Stock,
-			Category: util.Category{
-				ID:   p.CategoryID,
-				Name: p.CategoryName,
-			},
-		})
+	resp := make([]util.ProductResp, 0, len(page.Products))
+	for _, p := range page.Products {
+		resp = append(resp, toProductResp(p))
+	}
+
+	meta := &util.Pagination{
+		Limit:      filter.Limit,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+	if filter.Cursor == nil {
+		// Mode offset: link memakai page agar klien lama tetap bisa mengikuti nomor halaman
+		meta.Page = filter.Page
+		if filter.Page*filter.Limit < page.Total {
+			meta.Next = productPageLink(c, "page", strconv.Itoa(filter.Page+1))
+		}
+		if filter.Page > 1 {
+			meta.Prev = productPageLink(c, "page", strconv.Itoa(filter.Page-1))
+		}
+	} else {
+		if page.NextCursor != "" {
+			meta.Next = productPageLink(c, "cursor", page.NextCursor)
+		}
+		if page.PrevCursor != "" {
+			meta.Prev = productPageLink(c, "cursor", page.PrevCursor)
+		}
 	}
 
 	c.JSON(http.StatusOK, util.JSONResponse{
 		Message: "Success",
 		Data:    resp,
+		Meta:    meta,
 	})
 }
 
+// parseProductFilter membaca filter, urutan dan pagination daftar produk dari query string
+func parseProductFilter(c *gin.Context) (model.ProductFilter, error) {
+	filter := model.ProductFilter{
+		Name:  strings.TrimSpace(c.Query("name")),
+		Sort:  model.ProductSortID,
+		Page:  1,
+		Limit: 50,
+	}
+
+	if v := c.Query("category_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return filter, errors.New("invalid category_id")
+		}
+		filter.CategoryID = n
+	}
+
+	for name, target := range map[string]**model.Money{"min_price": &filter.MinPrice, "max_price": &filter.MaxPrice} {
+		if v := c.Query(name); v != "" {
+			m, err := model.ParseMoney(v)
+			if err != nil || m.IsNegative() {
+				return filter, errors.New("invalid " + name)
+			}
+			*target = &m
+		}
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && filter.MinPrice.Cmp(*filter.MaxPrice) > 0 {
+		return filter, errors.New("min_price tidak boleh lebih besar dari max_price")
+	}
+
+	if v := c.Query("in_stock"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errors.New("invalid in_stock, gunakan true atau false")
+		}
+		filter.InStock = &b
+	}
+
+	for name, target := range map[string]**int{"stock_min": &filter.StockMin, "stock_max": &filter.StockMax} {
+		if v := c.Query(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return filter, errors.New("invalid " + name)
+			}
+			*target = &n
+		}
+	}
+	if filter.StockMin != nil && filter.StockMax != nil && *filter.StockMin > *filter.StockMax {
+		return filter, errors.New("stock_min tidak boleh lebih besar dari stock_max")
+	}
+
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		return filter, err
+	}
+	filter.IncludeDeleted = includeDeleted
+
+	switch v := c.Query("sort"); v {
+	case "":
+	case model.ProductSortID, model.ProductSortName, model.ProductSortPrice, model.ProductSortStock:
+		filter.Sort = v
+	default:
+		return filter, errors.New("invalid sort, gunakan id, name, price atau stock")
+	}
+
+	switch c.Query("order") {
+	case "", "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return filter, errors.New("invalid order, gunakan asc atau desc")
+	}
+
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return filter, errors.New("invalid limit")
+		}
+		filter.Limit = n
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+
+	if v := c.Query("cursor"); v != "" {
+		if c.Query("page") != "" {
+			return filter, errors.New("page dan cursor tidak boleh dipakai bersamaan")
+		}
+		cursor, err := model.DecodeProductCursor(v)
+		if err != nil {
+			return filter, err
+		}
+		// Cursor hanya berlaku untuk urutan yang sama dengan saat cursor dibuat
+		if cursor.Sort != filter.Sort || cursor.Desc != filter.Desc {
+			return filter, fmt.Errorf("%w: sort/order berbeda dengan saat cursor dibuat", model.ErrInvalidCursor)
+		}
+		filter.Cursor = cursor
+		filter.Page = 0
+	} else if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return filter, errors.New("invalid page")
+		}
+		filter.Page = n
+	}
+
+	return filter, nil
+}
+
+// productPageLink membuat URL halaman lain dengan query yang sama, mengganti page/cursor
+func productPageLink(c *gin.Context, key, value string) string {
+	query := c.Request.URL.Query()
+	query.Del("page")
+	query.Del("cursor")
+	query.Set(key, value)
+	return c.Request.URL.Path + "?" + query.Encode()
+}
+
 // ============================
 // GET PRODUCT BY ID
 // ============================
 //
 // GetById godoc
 // @Summary Get product by ID
-// @Description Get product detail with category
+// @Description Get product detail with category. Soft-deleted products return 404 unless include_deleted=true.
 // @Tags products
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param include_deleted query bool false "Also return a soft-deleted product (admin)"
 // @Success 200 {object} util.JSONResponse{data=util.ProductResp}
+// @Header 200 {string} ETag "Product version, send it back in If-Match"
 // @Failure 400 {object} util.JSONResponse
 // @Failure 404 {object} util.JSONResponse
 // @Router /api/v1/products/{id} [get]
@@ -91,45 +250,89 @@ func (h *ProductHandler) GetById(c *gin.Context) {
 		return
 	}
 
-	product, err := h.service.GetByID(id)
+	includeDeleted, err := parseIncludeDeleted(c)
 	if err != nil {
-		c.JSON(http.StatusNotFound, util.JSONResponse{
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
 			Message: err.Error(),
 			Data:    nil,
 		})
 		return
 	}
 
-	resp := util.ProductResp{
-		ID:    product.ID,
-		Name:  product.Name,
-		Price: product.Price,
-		Stock: product.Stock,
-		Category: util.Category{
-			ID:   product.CategoryID,
-			Name: product.CategoryName,
-		},
+	product, err := h.service.GetByID(id, includeDeleted)
+	if err != nil {
+		c.JSON(http.StatusNotFound, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
 	}
 
+	resp := toProductResp(*product)
+
+	c.Header("ETag", model.ETag(product.Version))
 	c.JSON(http.StatusOK, util.JSONResponse{
 		Message: "Success",
 		Data:    resp,
 	})
 }
 
+// ============================
+// LOOKUP PRODUCT BY BARCODE
+// ============================
+//
+// Lookup godoc
+// @Summary Look up product by barcode
+// @Description Find the product that owns a scanned EAN-8, UPC-A or EAN-13 barcode. The check digit is validated before the lookup.
+// @Tags products
+// @Produce json
+// @Param barcode query string true "Scanned barcode"
+// @Success 200 {object} util.JSONResponse{data=util.ProductResp}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/lookup [get]
+func (h *ProductHandler) Lookup(c *gin.Context) {
+	barcode := c.Query("barcode")
+	if barcode == "" {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "barcode wajib diisi",
+			Data:    nil,
+		})
+		return
+	}
+
+	product, err := h.service.GetByBarcode(barcode)
+	if err != nil {
+		c.JSON(productErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "Success",
+		Data:    toProductResp(*product),
+	})
+}
+
 // ============================
 // CREATE PRODUCT
 // ============================
 //
 // Create godoc
 // @Summary Create product
-// @Description Create new product
+// @Description Create new product. sku must be unique and every barcode must be a valid EAN-8, UPC-A or EAN-13 not used by another product.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param product body model.Product true "Product payload"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
 // @Success 201 {object} util.JSONResponse{data=util.ProductResp}
+// @Header 201 {string} ETag "Product version"
 // @Failure 400 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
 // @Failure 500 {object} util.JSONResponse
 // @Router /api/v1/products [post]
 func (h *ProductHandler) Create(c *gin.Context) {
@@ -142,8 +345,15 @@ func (h *ProductHandler) Create(c *gin.Context) {
 		return
 	}
 
-	product, err := h.service.Create(&payload)
+	product, err := h.service.Create(&payload, actorFrom(c))
 	if err != nil {
+		if status := productErrorStatus(err); status != http.StatusInternalServerError {
+			c.JSON(status, util.JSONResponse{
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, util.JSONResponse{
 			Message: "Failed to create product",
 			Data:    nil,
@@ -151,17 +361,9 @@ func (h *ProductHandler) Create(c *gin.Context) {
 		return
 	}
 
-	resp := util.ProductResp{
-		ID:    product.ID,
-		Name:  product.Name,
-		Price: product.Price,
-		Stock: product.Stock,
-		Category: util.Category{
-			ID:   product.CategoryID,
-			Name: product.CategoryName,
-		},
-	}
+	resp := toProductResp(*product)
 
+	c.Header("ETag", model.ETag(product.Version))
 	c.JSON(http.StatusCreated, util.JSONResponse{
 		Message: "Product created successfully",
 		Data:    resp,
@@ -174,14 +376,21 @@ func (h *ProductHandler) Create(c *gin.Context) {
 //
 // Update godoc
 // @Summary Update product
-// @Description Update product by ID
+// @Description Update product by ID. The barcodes list replaces the existing barcodes of the product. cost_price is ignored; it is maintained by purchase order receiving. If-Match must carry the ETag of the version being replaced; the version changes on every change of the product, including stock movements.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param If-Match header string true "ETag returned by GET or the previous write"
 // @Param product body model.Product true "Product payload"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
 // @Success 200 {object} util.JSONResponse{data=util.ProductResp}
+// @Header 200 {string} ETag "New product version"
 // @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 412 {object} util.JSONResponse
+// @Failure 428 {object} util.JSONResponse
 // @Failure 500 {object} util.JSONResponse
 // @Router /api/v1/products/{id} [put]
 func (h *ProductHandler) Update(c *gin.Context) {
@@ -206,8 +415,30 @@ func (h *ProductHandler) Update(c *gin.Context) {
 
 	payload.ID = id
 
-	product, err := h.service.Update(&payload)
+	ifMatch, ok := ifMatchFrom(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.service.Update(&payload, ifMatch, actorFrom(c))
 	if err != nil {
+		if writeVersionMismatch(c, err) {
+			return
+		}
+		if errors.Is(err, model.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, util.JSONResponse{
+				Message: "Product not found",
+				Data:    nil,
+			})
+			return
+		}
+		if status := productErrorStatus(err); status != http.StatusInternalServerError {
+			c.JSON(status, util.JSONResponse{
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, util.JSONResponse{
 			Message: "Failed to update product",
 			Data:    nil,
@@ -215,36 +446,102 @@ func (h *ProductHandler) Update(c *gin.Context) {
 		return
 	}
 
-	resp := util.ProductResp{
-		ID:    product.ID,
-		Name:  product.Name,
-		Price: product.Price,
-		Stock: product.Stock,
-		Category: util.Category{
-			ID:   product.CategoryID,
-			Name: product.CategoryName,
-		},
-	}
+	resp := toProductResp(*product)
 
+	c.Header("ETag", model.ETag(product.Version))
 	c.JSON(http.StatusOK, util.JSONResponse{
 		Message: "Product updated successfully",
 		Data:    resp,
 	})
 }
 
+// ============================
+// PATCH PRODUCT
+// ============================
+//
+// Patch godoc
+// @Summary Partially update product
+// @Description Apply a JSON Merge Patch (RFC 7396) to a product. Only the supplied fields change, null clears a field, and the merged product is validated like a full update. If-Match must carry the ETag of the version being patched.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param If-Match header string true "ETag returned by GET or the previous write"
+// @Param patch body object true "Merge patch with only the fields to change"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 200 {object} util.JSONResponse{data=util.ProductResp}
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 412 {object} util.JSONResponse
+// @Failure 415 {object} util.JSONResponse
+// @Failure 428 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/{id} [patch]
+func (h *ProductHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return
+	}
+
+	patch, ok := readMergePatch(c)
+	if !ok {
+		return
+	}
+
+	ifMatch, ok := ifMatchFrom(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.service.Patch(id, patch, ifMatch, actorFrom(c))
+	if err != nil {
+		if writeVersionMismatch(c, err) {
+			return
+		}
+		if status := productErrorStatus(err); status != http.StatusInternalServerError {
+			c.JSON(status, util.JSONResponse{
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, util.JSONResponse{
+			Message: "Failed to update product",
+			Data:    nil,
+		})
+		return
+	}
+
+	c.Header("ETag", model.ETag(product.Version))
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "Product updated successfully",
+		Data:    toProductResp(*product),
+	})
+}
+
 // ============================
 // DELETE PRODUCT
 // ============================
 //
 // Delete godoc
 // @Summary Delete product
-// @Description Delete product by ID
+// @Description Soft-delete product by ID. The product is hidden from listings and can no longer be sold, but keeps its history, SKU and barcodes until it is restored or purged after the retention period.
 // @Tags products
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param If-Match header string true "ETag returned by GET or the previous write"
 // @Success 200 {object} util.JSONResponse
 // @Failure 400 {object} util.JSONResponse
 // @Failure 404 {object} util.JSONResponse
+// @Failure 412 {object} util.JSONResponse
+// @Failure 428 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
 // @Router /api/v1/products/{id} [delete]
 func (h *ProductHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
@@ -257,9 +554,17 @@ func (h *ProductHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	err = h.service.Delete(id)
+	ifMatch, ok := ifMatchFrom(c)
+	if !ok {
+		return
+	}
+
+	err = h.service.Delete(id, ifMatch)
 	if err != nil {
-		c.JSON(http.StatusNotFound, util.JSONResponse{
+		if writeVersionMismatch(c, err) {
+			return
+		}
+		c.JSON(productErrorStatus(err), util.JSONResponse{
 			Message: err.Error(),
 			Data:    nil,
 		})
@@ -271,3 +576,99 @@ func (h *ProductHandler) Delete(c *gin.Context) {
 		Data:    nil,
 	})
 }
+
+// ============================
+// RESTORE PRODUCT
+// ============================
+//
+// Restore godoc
+// @Summary Restore product
+// @Description Restore a soft-deleted product. Its category must not be deleted; restore the category first.
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} util.JSONResponse{data=util.ProductResp}
+// @Header 200 {string} ETag "New product version"
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/restore [post]
+func (h *ProductHandler) Restore(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return
+	}
+
+	product, err := h.service.Restore(id)
+	if err != nil {
+		c.JSON(productErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.Header("ETag", model.ETag(product.Version))
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "Product restored successfully",
+		Data:    toProductResp(*product),
+	})
+}
+
+// parseIncludeDeleted membaca query include_deleted untuk menampilkan data yang sudah dihapus (soft delete)
+func parseIncludeDeleted(c *gin.Context) (bool, error) {
+	v := c.Query("include_deleted")
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.New("invalid include_deleted, gunakan true atau false")
+	}
+	return b, nil
+}
+
+// productErrorStatus memetakan error validasi produk, SKU/barcode dan status hapus produk ke HTTP status
+func productErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, model.ErrInvalidBarcode), errors.Is(err, model.ErrInvalidSKU),
+		errors.Is(err, model.ErrInvalidProduct), errors.Is(err, util.ErrInvalidMergePatch):
+		return http.StatusBadRequest
+	case errors.Is(err, model.ErrProductNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, model.ErrDuplicateSKU), errors.Is(err, model.ErrDuplicateBarcode), errors.Is(err, model.ErrProductHasVariants),
+		errors.Is(err, model.ErrProductNotDeleted), errors.Is(err, model.ErrCategoryDeleted):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// toProductResp mengubah model produk (flat) menjadi response dengan kategori nested
+func toProductResp(p model.Product) util.ProductResp {
+	return util.ProductResp{
+		ID:          p.ID,
+		Name:        p.Name,
+		Price:       p.Price,
+		Stock:       p.Stock,
+		CostPrice:   p.CostPrice,
+		MinStock:    p.MinStock,
+		ReorderQty:  p.ReorderQty,
+		TaxRateID:   p.TaxRateID,
+		SKU:         p.SKU,
+		Barcodes:    p.Barcodes,
+		HasVariants: p.HasVariants,
+		Category: util.Category{
+			ID:   p.CategoryID,
+			Name: p.CategoryName,
+		},
+		DeletedAt: p.DeletedAt,
+		Version:   p.Version,
+	}
+}
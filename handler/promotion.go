@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"simple-crud/models"
+	"simple-crud/service"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionHandler struct {
+	service service.PromotionService
+}
+
+func NewPromotionHandler(svc service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{service: svc}
+}
+
+// ============================
+// GET ALL
+// ============================
+//
+// GetAll godoc
+// @Summary Get all promotions
+// @Description Retrieve all promotions, or only those currently active when active=true
+// @Tags promotions
+// @Produce json
+// @Param active query bool false "Only promotions that are active and within their validity window"
+// @Success 200 {object} util.JSONResponse{data=[]models.Promotion}
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/promotions [get]
+func (h *PromotionHandler) GetAll(c *gin.Context) {
+	promotions, err := h.service.GetAll(c.Query("active") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "promotions retrieved",
+		Data:    promotions,
+	})
+}
+
+// ============================
+// GET BY ID
+// ============================
+//
+// GetByID godoc
+// @Summary Get promotion by ID
+// @Description Get promotion detail by ID
+// @Tags promotions
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} util.JSONResponse{data=models.Promotion}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Router /api/v1/promotions/{id} [get]
+func (h *PromotionHandler) GetByID(c *gin.Context) {
+	id, ok := parsePromotionID(c)
+	if !ok {
+		return
+	}
+
+	promotion, err := h.service.GetByID(id)
+	if err != nil {
+		c.JSON(promotionErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "promotion retrieved",
+		Data:    promotion,
+	})
+}
+
+// ============================
+// CREATE
+// ============================
+//
+// Create godoc
+// @Summary Create promotion
+// @Description Create a percent, buy_x_get_y or cart_fixed promotion. Active defaults to true.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param promotion body models.Promotion true "Promotion payload"
+// @Success 201 {object} util.JSONResponse{data=models.Promotion}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/promotions [post]
+func (h *PromotionHandler) Create(c *gin.Context) {
+	payload := models.Promotion{Active: true}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	created, err := h.service.Create(payload)
+	if err != nil {
+		c.JSON(promotionErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "promotion created",
+		Data:    created,
+	})
+}
+
+// ============================
+// UPDATE
+// ============================
+//
+// Update godoc
+// @Summary Update promotion
+// @Description Update promotion by ID
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Param promotion body models.Promotion true "Promotion payload"
+// @Success 200 {object} util.JSONResponse{data=models.Promotion}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/promotions/{id} [put]
+func (h *PromotionHandler) Update(c *gin.Context) {
+	id, ok := parsePromotionID(c)
+	if !ok {
+		return
+	}
+
+	payload := models.Promotion{Active: true}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	updated, err := h.service.Update(id, payload)
+	if err != nil {
+		c.JSON(promotionErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "promotion updated",
+		Data:    updated,
+	})
+}
+
+// ============================
+// DELETE
+// ============================
+//
+// Delete godoc
+// @Summary Delete promotion
+// @Description Delete promotion by ID
+// @Tags promotions
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} util.JSONResponse
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Router /api/v1/promotions/{id} [delete]
+func (h *PromotionHandler) Delete(c *gin.Context) {
+	id, ok := parsePromotionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		c.JSON(promotionErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "promotion deleted",
+		Data: gin.H{
+			"id": id,
+		},
+	})
+}
+
+func parsePromotionID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// promotionErrorStatus memetakan error promo ke HTTP status
+func promotionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrPromotionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidPromotion):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,339 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"simple-crud/models"
+	"simple-crud/service"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PurchaseOrderHandler struct {
+	service service.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(svc service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: svc}
+}
+
+// ============================
+// GET ALL
+// ============================
+//
+// GetAll godoc
+// @Summary List purchase orders
+// @Description List purchase orders without lines, newest first
+// @Tags purchase-orders
+// @Produce json
+// @Param status query string false "Filter by status" Enums(draft, sent, partially_received, received, cancelled)
+// @Param supplier_id query int false "Filter by supplier"
+// @Success 200 {object} util.JSONResponse{data=[]models.PurchaseOrder}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/purchase-orders [get]
+func (h *PurchaseOrderHandler) GetAll(c *gin.Context) {
+	var filter models.PurchaseOrderFilter
+
+	filter.Status = c.Query("status")
+	switch filter.Status {
+	case "", models.POStatusDraft, models.POStatusSent, models.POStatusPartiallyReceived, models.POStatusReceived, models.POStatusCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid status",
+			Data:    nil,
+		})
+		return
+	}
+
+	if v := c.Query("supplier_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "invalid supplier_id",
+				Data:    nil,
+			})
+			return
+		}
+		filter.SupplierID = id
+	}
+
+	orders, err := h.service.GetAll(filter)
+	if err != nil {
+		writePurchaseOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "purchase orders retrieved",
+		Data:    orders,
+	})
+}
+
+// ============================
+// GET BY ID
+// ============================
+//
+// GetByID godoc
+// @Summary Get purchase order
+// @Description Get a purchase order with its lines and receiving history
+// @Tags purchase-orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} util.JSONResponse{data=models.PurchaseOrder}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetByID(c *gin.Context) {
+	id, ok := parsePurchaseOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.GetByID(id)
+	if err != nil {
+		writePurchaseOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "purchase order retrieved",
+		Data:    order,
+	})
+}
+
+// ============================
+// CREATE
+// ============================
+//
+// Create godoc
+// @Summary Create purchase order
+// @Description Create a draft purchase order for a supplier. Lines for products with variants require variant_id.
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param purchase_order body models.PurchaseOrderRequest true "Purchase order payload"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 201 {object} util.JSONResponse{data=models.PurchaseOrder}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/purchase-orders [post]
+func (h *PurchaseOrderHandler) Create(c *gin.Context) {
+	var req models.PurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+		return
+	}
+
+	order, err := h.service.Create(req, actorFrom(c))
+	if err != nil {
+		writePurchaseOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "purchase order created",
+		Data:    order,
+	})
+}
+
+// ============================
+// UPDATE
+// ============================
+//
+// Update godoc
+// @Summary Update draft purchase order
+// @Description Replace the supplier, note and all lines of a draft purchase order
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param purchase_order body models.PurchaseOrderRequest true "Purchase order payload"
+// @Success 200 {object} util.JSONResponse{data=models.PurchaseOrder}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) Update(c *gin.Context) {
+	id, ok := parsePurchaseOrderID(c)
+	if !ok {
+		return
+	}
+
+	var req models.PurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+		return
+	}
+
+	order, err := h.service.Update(id, req)
+	if err != nil {
+		writePurchaseOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "purchase order updated",
+		Data:    order,
+	})
+}
+
+// ============================
+// SEND
+// ============================
+//
+// Send godoc
+// @Summary Send purchase order
+// @Description Mark a draft purchase order as sent to the supplier. Lines can no longer be changed.
+// @Tags purchase-orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} util.JSONResponse{data=models.PurchaseOrder}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/purchase-orders/{id}/send [post]
+func (h *PurchaseOrderHandler) Send(c *gin.Context) {
+	id, ok := parsePurchaseOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.Send(id)
+	if err != nil {
+		writePurchaseOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "purchase order sent",
+		Data:    order,
+	})
+}
+
+// ============================
+// CANCEL
+// ============================
+//
+// Cancel godoc
+// @Summary Cancel purchase order
+// @Description Cancel a draft, sent or partially received purchase order. Stock already received is kept.
+// @Tags purchase-orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} util.JSONResponse{data=models.PurchaseOrder}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/purchase-orders/{id}/cancel [post]
+func (h *PurchaseOrderHandler) Cancel(c *gin.Context) {
+	id, ok := parsePurchaseOrderID(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.Cancel(id)
+	if err != nil {
+		writePurchaseOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "purchase order cancelled",
+		Data:    order,
+	})
+}
+
+// ============================
+// RECEIVE
+// ============================
+//
+// Receive godoc
+// @Summary Receive goods
+// @Description Record a (partial) delivery for a sent purchase order: increments product stock (and variant stock for variant lines), records the actual unit cost and a receiving entry in the stock ledger, then moves the order to partially_received or received
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param receipt body models.ReceiveRequest true "Received quantities per line"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 200 {object} util.JSONResponse{data=models.PurchaseOrder}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 422 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) Receive(c *gin.Context) {
+	id, ok := parsePurchaseOrderID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReceiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+		return
+	}
+
+	order, err := h.service.Receive(id, req, actorFrom(c))
+	if err != nil {
+		writePurchaseOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "goods received",
+		Data:    order,
+	})
+}
+
+func parsePurchaseOrderID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// writePurchaseOrderError memetakan error purchase order ke HTTP status
+func writePurchaseOrderError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrInvalidPurchaseOrder), errors.Is(err, models.ErrVariantRequired):
+		status = http.StatusBadRequest
+	case errors.Is(err, models.ErrPurchaseOrderNotFound), errors.Is(err, models.ErrSupplierNotFound), errors.Is(err, models.ErrProductNotFound),
+		errors.Is(err, models.ErrVariantNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrPurchaseOrderStatus), errors.Is(err, models.ErrProductHasVariants):
+		status = http.StatusConflict
+	case errors.Is(err, models.ErrOverReceive):
+		status = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(status, util.JSONResponse{
+		Message: err.Error(),
+		Data:    nil,
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"simple-crud/models"
+	"simple-crud/service"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+var receiptContentTypes = map[string]string{
+	models.ReceiptFormatText:   "text/plain; charset=utf-8",
+	models.ReceiptFormatHTML:   "text/html; charset=utf-8",
+	models.ReceiptFormatESCPOS: "application/octet-stream",
+}
+
+type ReceiptHandler struct {
+	service service.ReceiptService
+}
+
+func NewReceiptHandler(svc service.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{
+		service: svc,
+	}
+}
+
+// ============================
+// RENDER RECEIPT
+// ============================
+//
+// GetReceipt godoc
+// @Summary Render transaction receipt
+// @Description Render a receipt with store header, line items, totals, payments and footer. escpos returns raw ESC/POS bytes for 58mm/80mm thermal printers.
+// @Tags transactions
+// @Produce plain
+// @Produce html
+// @Produce octet-stream
+// @Param id path int true "Transaction ID"
+// @Param format query string false "Receipt format (default txt)" Enums(txt, html, escpos)
+// @Param paper query int false "Paper width in mm (default from RECEIPT_PAPER_WIDTH)" Enums(58, 80)
+// @Success 200 {string} string
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/transactions/{id}/receipt [get]
+func (h *ReceiptHandler) GetReceipt(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", models.ReceiptFormatText)
+	contentType, ok := receiptContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: models.ErrUnsupportedReceiptFormat.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	paperWidth := 0
+	if v := c.Query("paper"); v != "" {
+		paperWidth, err = strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: models.ErrInvalidPaperWidth.Error(),
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	receipt, err := h.service.Render(id, format, paperWidth)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, models.ErrTransactionNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, models.ErrInvalidPaperWidth), errors.Is(err, models.ErrUnsupportedReceiptFormat):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	if format == models.ReceiptFormatESCPOS {
+		c.Header("Content-Disposition", "attachment; filename=receipt-"+strconv.Itoa(id)+".bin")
+	}
+	c.Data(http.StatusOK, contentType, receipt)
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"simple-crud/service"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockAlertHandler struct {
+	service      service.StockAlertService
+	pollInterval time.Duration
+}
+
+// NewStockAlertHandler: pollInterval adalah jeda pengecekan alert baru untuk stream SSE
+func NewStockAlertHandler(svc service.StockAlertService, pollInterval time.Duration) *StockAlertHandler {
+	if pollInterval <= 0 {
+		pollInterval = 2 * time.Second
+	}
+	return &StockAlertHandler{service: svc, pollInterval: pollInterval}
+}
+
+// ============================
+// LOW STOCK
+// ============================
+//
+// LowStock godoc
+// @Summary List low-stock products
+// @Description Products with min_stock > 0 whose stock is at or below min_stock, most critical first, with sales velocity and a suggested reorder quantity: max(reorder_qty, ceil(avg_daily_sales x cover_days) + min_stock - stock)
+// @Tags products
+// @Produce json
+// @Param velocity_days query int false "Sales history window in days (default from LOW_STOCK_VELOCITY_DAYS)"
+// @Param cover_days query int false "Days of sales the reorder should cover (default from LOW_STOCK_COVER_DAYS)"
+// @Success 200 {object} util.JSONResponse{data=[]models.LowStockProduct}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/low-stock [get]
+func (h *StockAlertHandler) LowStock(c *gin.Context) {
+	velocityDays, ok := parseOptionalPositiveInt(c, "velocity_days", 365)
+	if !ok {
+		return
+	}
+	coverDays, ok := parseOptionalPositiveInt(c, "cover_days", 365)
+	if !ok {
+		return
+	}
+
+	products, err := h.service.GetLowStock(velocityDays, coverDays)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "Success",
+		Data:    products,
+	})
+}
+
+// ============================
+// ALERT FEED (POLLING)
+// ============================
+//
+// GetAll godoc
+// @Summary Poll stock alerts
+// @Description Alerts raised when a checkout pushes a product to or below its min_stock, oldest first. Pass the last received id as after_id to get only new alerts.
+// @Tags stock-alerts
+// @Produce json
+// @Param after_id query int false "Only alerts with id greater than this (default 0)"
+// @Param limit query int false "Max alerts (default 50, max 200)"
+// @Success 200 {object} util.JSONResponse{data=[]models.StockAlert}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/stock-alerts [get]
+func (h *StockAlertHandler) GetAll(c *gin.Context) {
+	afterID := 0
+	if v := c.Query("after_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "invalid after_id",
+				Data:    nil,
+			})
+			return
+		}
+		afterID = n
+	}
+
+	limit, ok := parseOptionalPositiveInt(c, "limit", 200)
+	if !ok {
+		return
+	}
+	if limit == 0 {
+		limit = 50
+	}
+
+	alerts, err := h.service.GetAfter(afterID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "Success",
+		Data:    alerts,
+	})
+}
+
+// ============================
+// ALERT FEED (SSE)
+// ============================
+//
+// Stream godoc
+// @Summary Stream stock alerts (SSE)
+// @Description Server-Sent Events stream of new stock alerts (event "stock-alert", id = alert id, data = StockAlert JSON). Resumes after the Last-Event-ID header or after_id query if given, otherwise only alerts raised after connecting are sent.
+// @Tags stock-alerts
+// @Produce text/event-stream
+// @Param after_id query int false "Resume after this alert id"
+// @Param Last-Event-ID header int false "Resume after this alert id (set automatically by EventSource on reconnect)"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/stock-alerts/stream [get]
+func (h *StockAlertHandler) Stream(c *gin.Context) {
+	lastID := -1
+	for _, v := range []string{c.GetHeader("Last-Event-ID"), c.Query("after_id")} {
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "invalid after_id",
+				Data:    nil,
+			})
+			return
+		}
+		lastID = n
+		break
+	}
+	if lastID < 0 {
+		id, err := h.service.LatestID()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, util.JSONResponse{
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+		lastID = id
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, ": connected\n\n")
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+	idle := time.Duration(0)
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		alerts, err := h.service.GetAfter(lastID, 200)
+		if err != nil {
+			log.Println("stock alert stream:", err)
+			continue
+		}
+
+		// Komentar keep-alive agar proxy tidak memutus koneksi yang lama tidak mengirim data
+		if len(alerts) == 0 {
+			idle += h.pollInterval
+			if idle >= 15*time.Second {
+				fmt.Fprint(c.Writer, ": keep-alive\n\n")
+				c.Writer.Flush()
+				idle = 0
+			}
+			continue
+		}
+
+		idle = 0
+		for _, alert := range alerts {
+			data, err := json.Marshal(alert)
+			if err != nil {
+				log.Println("stock alert stream:", err)
+				continue
+			}
+			fmt.Fprintf(c.Writer, "id: %d\nevent: stock-alert\ndata: %s\n\n", alert.ID, data)
+			lastID = alert.ID
+		}
+		c.Writer.Flush()
+	}
+}
+
+// parseOptionalPositiveInt membaca query integer opsional 1..max; 0 berarti tidak diisi
+func parseOptionalPositiveInt(c *gin.Context, name string, max int) (int, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > max {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: fmt.Sprintf("invalid %s, harus antara 1 dan %d", name, max),
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return n, true
+}
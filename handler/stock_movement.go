@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"simple-crud/models"
+	"simple-crud/service"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockMovementHandler struct {
+	service service.StockMovementService
+}
+
+func NewStockMovementHandler(svc service.StockMovementService) *StockMovementHandler {
+	return &StockMovementHandler{service: svc}
+}
+
+// actorFrom mengambil nama/ID user yang melakukan perubahan dari header X-Actor untuk ledger stok
+func actorFrom(c *gin.Context) string {
+	actor := strings.TrimSpace(c.GetHeader("X-Actor"))
+	if actor == "" {
+		return models.DefaultActor
+	}
+	if len(actor) > 100 {
+		actor = actor[:100]
+	}
+	return actor
+}
+
+// ============================
+// STOCK MOVEMENTS
+// ============================
+//
+// GetByProduct godoc
+// @Summary List stock movements of a product
+// @Description Audit ledger of every change to the product stock (sale, refund, adjustment, receiving, initial), newest first
+// @Tags products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param reason query string false "Filter by reason" Enums(sale, refund, adjustment, receiving, initial)
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Success 200 {object} util.JSONResponse{data=[]models.StockMovement}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/stock-movements [get]
+func (h *StockMovementHandler) GetByProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return
+	}
+
+	filter, err := parseStockMovementFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	movements, total, err := h.service.GetByProduct(id, filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrProductNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "stock movements retrieved",
+		Data:    movements,
+		Meta: &util.Pagination{
+			Page:  filter.Page,
+			Limit: filter.Limit,
+			Total: total,
+		},
+	})
+}
+
+// ============================
+// STOCK ADJUSTMENT
+// ============================
+//
+// Adjust godoc
+// @Summary Adjust product stock
+// @Description Change the stock of a product by a signed delta with a reason code, without touching name, price or category. The change is recorded in the stock ledger as an adjustment. Products with variants require variant_id; the variant and the parent product stock change together.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param adjustment body models.StockAdjustmentRequest true "Adjustment payload"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 201 {object} util.JSONResponse{data=models.StockMovement}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/stock-adjustments [post]
+func (h *StockMovementHandler) Adjust(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req models.StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+		return
+	}
+
+	movement, err := h.service.Adjust(id, req, actorFrom(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, models.ErrInvalidStockAdjustment), errors.Is(err, models.ErrVariantRequired):
+			status = http.StatusBadRequest
+		case errors.Is(err, models.ErrProductNotFound), errors.Is(err, models.ErrVariantNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, models.ErrNegativeStock):
+			status = http.StatusConflict
+		}
+		c.JSON(status, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "stock adjusted",
+		Data:    movement,
+	})
+}
+
+func parseStockMovementFilter(c *gin.Context) (models.StockMovementFilter, error) {
+	filter := models.StockMovementFilter{Page: 1, Limit: 20}
+
+	if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return filter, errors.New("invalid page")
+		}
+		filter.Page = n
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return filter, errors.New("invalid limit")
+		}
+		filter.Limit = n
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	if v := c.Query("reason"); v != "" {
+		if !models.IsValidStockReason(v) {
+			return filter, errors.New("invalid reason")
+		}
+		filter.Reason = v
+	}
+
+	for name, target := range map[string]*string{"start_date": &filter.StartDate, "end_date": &filter.EndDate} {
+		if v := c.Query(name); v != "" {
+			if _, err := time.Parse("2006-01-02", v); err != nil {
+				return filter, errors.New("invalid " + name + ", gunakan format YYYY-MM-DD")
+			}
+			*target = v
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"simple-crud/models"
+	"simple-crud/service"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockTakeHandler struct {
+	service service.StockTakeService
+}
+
+func NewStockTakeHandler(svc service.StockTakeService) *StockTakeHandler {
+	return &StockTakeHandler{service: svc}
+}
+
+// ============================
+// GET ALL
+// ============================
+//
+// GetAll godoc
+// @Summary List stock-take sessions
+// @Description List stock-take sessions without items, newest first
+// @Tags stock-takes
+// @Produce json
+// @Param status query string false "Filter by status" Enums(open, committed, cancelled)
+// @Success 200 {object} util.JSONResponse{data=[]models.StockTake}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/stock-takes [get]
+func (h *StockTakeHandler) GetAll(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", models.StockTakeStatusOpen, models.StockTakeStatusCommitted, models.StockTakeStatusCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid status",
+			Data:    nil,
+		})
+		return
+	}
+
+	takes, err := h.service.GetAll(status)
+	if err != nil {
+		writeStockTakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "stock takes retrieved",
+		Data:    takes,
+	})
+}
+
+// ============================
+// GET BY ID (VARIANCE REPORT)
+// ============================
+//
+// GetByID godoc
+// @Summary Get stock-take variance report
+// @Description Get a stock-take session with counted vs system quantity per product. System quantity is the product stock at the time the product was counted.
+// @Tags stock-takes
+// @Produce json
+// @Param id path int true "Stock-take ID"
+// @Success 200 {object} util.JSONResponse{data=models.StockTake}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/stock-takes/{id} [get]
+func (h *StockTakeHandler) GetByID(c *gin.Context) {
+	id, ok := parseStockTakeID(c)
+	if !ok {
+		return
+	}
+
+	take, err := h.service.GetByID(id)
+	if err != nil {
+		writeStockTakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "stock take retrieved",
+		Data:    take,
+	})
+}
+
+// ============================
+// START
+// ============================
+//
+// Start godoc
+// @Summary Start stock-take
+// @Description Open a new stock count session
+// @Tags stock-takes
+// @Accept json
+// @Produce json
+// @Param stock_take body models.CreateStockTakeRequest false "Stock-take payload"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 201 {object} util.JSONResponse{data=models.StockTake}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/stock-takes [post]
+func (h *StockTakeHandler) Start(c *gin.Context) {
+	var req models.CreateStockTakeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "Invalid request body",
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	take, err := h.service.Start(req, actorFrom(c))
+	if err != nil {
+		writeStockTakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "stock take started",
+		Data:    take,
+	})
+}
+
+// ============================
+// SUBMIT COUNTS
+// ============================
+//
+// SubmitCounts godoc
+// @Summary Submit counted quantities
+// @Description Record counted quantities for many products in an open session. The current stock is snapshotted as the system quantity of each count. Products with variants are counted per variant (variant_id), with the variant stock as the system quantity. Counting a product or variant again replaces its previous count and snapshot. Returns the updated variance report.
+// @Tags stock-takes
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock-take ID"
+// @Param counts body models.StockTakeCountRequest true "Counted quantities"
+// @Success 200 {object} util.JSONResponse{data=models.StockTake}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/stock-takes/{id}/items [put]
+func (h *StockTakeHandler) SubmitCounts(c *gin.Context) {
+	id, ok := parseStockTakeID(c)
+	if !ok {
+		return
+	}
+
+	var req models.StockTakeCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+		return
+	}
+	if len(req.Items) == 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "items wajib diisi",
+			Data:    nil,
+		})
+		return
+	}
+	for _, item := range req.Items {
+		if item.ProductID <= 0 || item.VariantID < 0 || item.CountedQuantity < 0 {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "product_id harus lebih dari 0, variant_id dan counted_quantity tidak boleh negatif",
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	take, err := h.service.SubmitCounts(id, req)
+	if err != nil {
+		writeStockTakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "counts saved",
+		Data:    take,
+	})
+}
+
+// ============================
+// COMMIT
+// ============================
+//
+// Commit godoc
+// @Summary Commit stock-take
+// @Description Atomically add the variance of every counted product (counted minus the system quantity at count time) to its current stock and record it as an adjustment in the stock ledger, so sales and receiving after counting are kept
+// @Tags stock-takes
+// @Produce json
+// @Param id path int true "Stock-take ID"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 200 {object} util.JSONResponse{data=models.StockTake}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 422 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/stock-takes/{id}/commit [post]
+func (h *StockTakeHandler) Commit(c *gin.Context) {
+	id, ok := parseStockTakeID(c)
+	if !ok {
+		return
+	}
+
+	take, err := h.service.Commit(id, actorFrom(c))
+	if err != nil {
+		writeStockTakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "stock take committed",
+		Data:    take,
+	})
+}
+
+// ============================
+// CANCEL
+// ============================
+//
+// Cancel godoc
+// @Summary Cancel stock-take
+// @Description Close an open session without changing any stock
+// @Tags stock-takes
+// @Produce json
+// @Param id path int true "Stock-take ID"
+// @Success 200 {object} util.JSONResponse{data=models.StockTake}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/stock-takes/{id}/cancel [post]
+func (h *StockTakeHandler) Cancel(c *gin.Context) {
+	id, ok := parseStockTakeID(c)
+	if !ok {
+		return
+	}
+
+	take, err := h.service.Cancel(id)
+	if err != nil {
+		writeStockTakeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "stock take cancelled",
+		Data:    take,
+	})
+}
+
+func parseStockTakeID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// writeStockTakeError memetakan error stock-take ke HTTP status
+func writeStockTakeError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrStockTakeNotFound), errors.Is(err, models.ErrProductNotFound), errors.Is(err, models.ErrVariantNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrVariantRequired):
+		status = http.StatusBadRequest
+	case errors.Is(err, models.ErrStockTakeNotOpen), errors.Is(err, models.ErrProductHasVariants):
+		status = http.StatusConflict
+	case errors.Is(err, models.ErrStockTakeEmpty):
+		status = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(status, util.JSONResponse{
+		Message: err.Error(),
+		Data:    nil,
+	})
+}
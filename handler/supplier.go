@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"simple-crud/models"
+	"simple-crud/service"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SupplierHandler struct {
+	service service.SupplierService
+}
+
+func NewSupplierHandler(svc service.SupplierService) *SupplierHandler {
+	return &SupplierHandler{service: svc}
+}
+
+// ============================
+// GET ALL
+// ============================
+//
+// GetAll godoc
+// @Summary Get all suppliers
+// @Description Retrieve all suppliers ordered by name
+// @Tags suppliers
+// @Produce json
+// @Success 200 {object} util.JSONResponse{data=[]models.Supplier}
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/suppliers [get]
+func (h *SupplierHandler) GetAll(c *gin.Context) {
+	suppliers, err := h.service.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "suppliers retrieved",
+		Data:    suppliers,
+	})
+}
+
+// ============================
+// GET BY ID
+// ============================
+//
+// GetByID godoc
+// @Summary Get supplier by ID
+// @Description Get supplier detail by ID
+// @Tags suppliers
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} util.JSONResponse{data=models.Supplier}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Router /api/v1/suppliers/{id} [get]
+func (h *SupplierHandler) GetByID(c *gin.Context) {
+	id, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	supplier, err := h.service.GetByID(id)
+	if err != nil {
+		c.JSON(supplierErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "supplier retrieved",
+		Data:    supplier,
+	})
+}
+
+// ============================
+// CREATE
+// ============================
+//
+// Create godoc
+// @Summary Create supplier
+// @Description Create a new supplier
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Param supplier body models.Supplier true "Supplier payload"
+// @Success 201 {object} util.JSONResponse{data=models.Supplier}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/suppliers [post]
+func (h *SupplierHandler) Create(c *gin.Context) {
+	var payload models.Supplier
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	created, err := h.service.Create(payload)
+	if err != nil {
+		c.JSON(supplierErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "supplier created",
+		Data:    created,
+	})
+}
+
+// ============================
+// UPDATE
+// ============================
+//
+// Update godoc
+// @Summary Update supplier
+// @Description Update supplier by ID
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Param supplier body models.Supplier true "Supplier payload"
+// @Success 200 {object} util.JSONResponse{data=models.Supplier}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/suppliers/{id} [put]
+func (h *SupplierHandler) Update(c *gin.Context) {
+	id, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	var payload models.Supplier
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	updated, err := h.service.Update(id, payload)
+	if err != nil {
+		c.JSON(supplierErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "supplier updated",
+		Data:    updated,
+	})
+}
+
+// ============================
+// DELETE
+// ============================
+//
+// Delete godoc
+// @Summary Delete supplier
+// @Description Delete a supplier that has no purchase orders
+// @Tags suppliers
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} util.JSONResponse
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/suppliers/{id} [delete]
+func (h *SupplierHandler) Delete(c *gin.Context) {
+	id, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		c.JSON(supplierErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "supplier deleted",
+		Data:    nil,
+	})
+}
+
+func parseSupplierID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// supplierErrorStatus memetakan error supplier ke HTTP status
+func supplierErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrSupplierNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidSupplier):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrSupplierInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
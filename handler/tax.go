@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"simple-crud/models"
+	"simple-crud/service"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaxRateHandler struct {
+	service service.TaxRateService
+}
+
+func NewTaxRateHandler(svc service.TaxRateService) *TaxRateHandler {
+	return &TaxRateHandler{service: svc}
+}
+
+// ============================
+// GET ALL
+// ============================
+//
+// GetAll godoc
+// @Summary Get all tax rates
+// @Description Retrieve all tax rates
+// @Tags tax-rates
+// @Produce json
+// @Success 200 {object} util.JSONResponse{data=[]models.TaxRate}
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/tax-rates [get]
+func (h *TaxRateHandler) GetAll(c *gin.Context) {
+	rates, err := h.service.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "tax rates retrieved",
+		Data:    rates,
+	})
+}
+
+// ============================
+// GET BY ID
+// ============================
+//
+// GetByID godoc
+// @Summary Get tax rate by ID
+// @Description Get tax rate detail by ID
+// @Tags tax-rates
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} util.JSONResponse{data=models.TaxRate}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Router /api/v1/tax-rates/{id} [get]
+func (h *TaxRateHandler) GetByID(c *gin.Context) {
+	id, ok := parseTaxRateID(c)
+	if !ok {
+		return
+	}
+
+	rate, err := h.service.GetByID(id)
+	if err != nil {
+		c.JSON(taxRateErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "tax rate retrieved",
+		Data:    rate,
+	})
+}
+
+// ============================
+// CREATE
+// ============================
+//
+// Create godoc
+// @Summary Create tax rate
+// @Description Create an inclusive or exclusive tax rate (e.g. PPN 11% = rate_bp 1100). Active defaults to true.
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param tax_rate body models.TaxRate true "Tax rate payload"
+// @Success 201 {object} util.JSONResponse{data=models.TaxRate}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/tax-rates [post]
+func (h *TaxRateHandler) Create(c *gin.Context) {
+	payload := models.TaxRate{Active: true}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	created, err := h.service.Create(payload)
+	if err != nil {
+		c.JSON(taxRateErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "tax rate created",
+		Data:    created,
+	})
+}
+
+// ============================
+// UPDATE
+// ============================
+//
+// Update godoc
+// @Summary Update tax rate
+// @Description Update tax rate by ID
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Param tax_rate body models.TaxRate true "Tax rate payload"
+// @Success 200 {object} util.JSONResponse{data=models.TaxRate}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/tax-rates/{id} [put]
+func (h *TaxRateHandler) Update(c *gin.Context) {
+	id, ok := parseTaxRateID(c)
+	if !ok {
+		return
+	}
+
+	payload := models.TaxRate{Active: true}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	updated, err := h.service.Update(id, payload)
+	if err != nil {
+		c.JSON(taxRateErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "tax rate updated",
+		Data:    updated,
+	})
+}
+
+// ============================
+// DELETE
+// ============================
+//
+// Delete godoc
+// @Summary Delete tax rate
+// @Description Delete tax rate by ID
+// @Tags tax-rates
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} util.JSONResponse
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Router /api/v1/tax-rates/{id} [delete]
+func (h *TaxRateHandler) Delete(c *gin.Context) {
+	id, ok := parseTaxRateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		c.JSON(taxRateErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "tax rate deleted",
+		Data: gin.H{
+			"id": id,
+		},
+	})
+}
+
+func parseTaxRateID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// taxRateErrorStatus memetakan error tarif pajak ke HTTP status
+func taxRateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrTaxRateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidTaxRate):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"simple-crud/models"
 	"simple-crud/service"
@@ -16,9 +20,14 @@ type ProdukTerlarisResp struct {
 }
 
 type SalesSummaryResp struct {
-	TotalRevenue   int                `json:"total_revenue"`
-	TotalTransaksi int                `json:"total_transaksi"`
-	ProdukTerlaris ProdukTerlarisResp `json:"produk_terlaris"`
+	TotalRevenue   models.Money                `json:"total_revenue" swaggertype:"number"` // revenue bersih
+	GrossRevenue   models.Money                `json:"gross_revenue" swaggertype:"number"`
+	TotalDiscount  models.Money                `json:"total_discount" swaggertype:"number"`
+	TotalRefund    models.Money                `json:"total_refund" swaggertype:"number"`
+	TotalTransaksi int                         `json:"total_transaksi"`
+	ProdukTerlaris ProdukTerlarisResp          `json:"produk_terlaris"`
+	Pembayaran     []models.PaymentMethodTotal `json:"pembayaran"` // rincian per metode pembayaran
+	Profit         models.ProfitReport         `json:"profit"`     // HPP, laba kotor dan margin per produk dan per kategori
 }
 
 type TransactionHandler struct {
@@ -37,13 +46,18 @@ func NewTransactionHandler(svc service.TransactionService) *TransactionHandler {
 //
 // Checkout godoc
 // @Summary Checkout transaction
-// @Description Create transaction from cart items and update product stock
+// @Description Create transaction from cart items and update product stock. Each item references a product by product_id or by a scanned barcode. Payments (split tender) must cover the total; change is only given from cash. Without payments the transaction is recorded as paid in exact cash.
 // @Tags transactions
 // @Accept json
 // @Produce json
-// @Param checkout body model.CheckoutRequest true "Checkout payload"
-// @Success 200 {object} util.JSONResponse{data=model.Transaction}
+// @Param Idempotency-Key header string false "Key unik per checkout; request ulang dengan key yang sama me-replay response awal"
+// @Param checkout body models.CheckoutRequest true "Checkout payload"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 200 {object} util.JSONResponse{data=models.Transaction}
 // @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse{data=[]models.InsufficientStockItem}
+// @Failure 422 {object} util.JSONResponse
 // @Failure 500 {object} util.JSONResponse
 // @Router /api/v1/checkout [post]
 func (h *TransactionHandler) Checkout(c *gin.Context) {
@@ -56,8 +70,106 @@ func (h *TransactionHandler) Checkout(c *gin.Context) {
 		return
 	}
 
-	transaction, err := h.service.Checkout(req.Items, false)
+	if len(req.Items) == 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "items tidak boleh kosong",
+			Data:    nil,
+		})
+		return
+	}
+	for i, item := range req.Items {
+		if item.ProductID <= 0 && item.VariantID <= 0 && item.Barcode == "" {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: fmt.Sprintf("item ke-%d wajib berisi product_id, variant_id atau barcode", i+1),
+				Data:    nil,
+			})
+			return
+		}
+		if item.ProductID <= 0 && item.VariantID <= 0 {
+			if err := models.ValidateBarcode(item.Barcode); err != nil {
+				c.JSON(http.StatusBadRequest, util.JSONResponse{
+					Message: err.Error(),
+					Data:    nil,
+				})
+				return
+			}
+		}
+		if item.Quantity <= 0 {
+			ref := fmt.Sprintf("product id %d", item.ProductID)
+			if item.VariantID > 0 {
+				ref = fmt.Sprintf("variant id %d", item.VariantID)
+			} else if item.ProductID <= 0 {
+				ref = "barcode " + item.Barcode
+			}
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: fmt.Sprintf("quantity untuk %s harus lebih dari 0", ref),
+				Data:    nil,
+			})
+			return
+		}
+	}
+	for _, payment := range req.Payments {
+		if err := payment.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	if len(idempotencyKey) > 255 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "Idempotency-Key maksimal 255 karakter",
+			Data:    nil,
+		})
+		return
+	}
+
+	var (
+		transaction *models.Transaction
+		replayed    bool
+		err         error
+	)
+	if idempotencyKey != "" {
+		transaction, replayed, err = h.service.CheckoutIdempotent(idempotencyKey, req, true, actorFrom(c))
+	} else {
+		transaction, err = h.service.Checkout(req, true, actorFrom(c))
+	}
 	if err != nil {
+		if errors.Is(err, models.ErrIdempotencyKeyMismatch) || errors.Is(err, models.ErrVoucherNotApplicable) ||
+			errors.Is(err, models.ErrInsufficientPayment) || errors.Is(err, models.ErrNonCashOverpayment) ||
+			errors.Is(err, models.ErrVariantRequired) {
+			c.JSON(http.StatusUnprocessableEntity, util.JSONResponse{
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+		if errors.Is(err, models.ErrProductNotFound) || errors.Is(err, models.ErrVariantNotFound) {
+			c.JSON(http.StatusNotFound, util.JSONResponse{
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+		if errors.Is(err, models.ErrIdempotencyKeyInProgress) {
+			c.JSON(http.StatusConflict, util.JSONResponse{
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+
+		var stockErr *models.ErrInsufficientStock
+		if errors.As(err, &stockErr) {
+			c.JSON(http.StatusConflict, util.JSONResponse{
+				Message: stockErr.Error(),
+				Data:    stockErr.Items,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, util.JSONResponse{
 			Message: err.Error(),
 			Data:    nil,
@@ -65,19 +177,317 @@ func (h *TransactionHandler) Checkout(c *gin.Context) {
 		return
 	}
 
+	if replayed {
+		c.Header("Idempotent-Replayed", "true")
+	}
+
 	c.JSON(http.StatusOK, util.JSONResponse{
 		Message: "Checkout berhasil",
 		Data:    transaction,
 	})
 }
 
+// ============================
+// LIST TRANSACTIONS
+// ============================
+//
+// GetTransactions godoc
+// @Summary List transactions
+// @Description List past transactions with pagination and optional date, amount and product filters
+// @Tags transactions
+// @Produce json
+// @Param page query int false "Page (default 1)"
+// @Param limit query int false "Items per page (default 20, max 100)"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param min_amount query number false "Minimum total amount"
+// @Param max_amount query number false "Maximum total amount"
+// @Param product_id query int false "Only transactions containing this product"
+// @Success 200 {object} util.JSONResponse{data=[]models.Transaction}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/transactions [get]
+func (h *TransactionHandler) GetTransactions(c *gin.Context) {
+	filter, err := parseTransactionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	transactions, total, err := h.service.GetTransactions(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "Success",
+		Data:    transactions,
+		Meta: &util.Pagination{
+			Page:  filter.Page,
+			Limit: filter.Limit,
+			Total: total,
+		},
+	})
+}
+
+// ============================
+// GET TRANSACTION BY ID
+// ============================
+//
+// GetTransactionByID godoc
+// @Summary Get transaction by ID
+// @Description Get transaction detail including line items and product names
+// @Tags transactions
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Success 200 {object} util.JSONResponse{data=models.Transaction}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/transactions/{id} [get]
+func (h *TransactionHandler) GetTransactionByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return
+	}
+
+	transaction, err := h.service.GetTransactionByID(id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrTransactionNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "Success",
+		Data:    transaction,
+	})
+}
+
+// ============================
+// VOID TRANSACTION
+// ============================
+//
+// VoidTransaction godoc
+// @Summary Void transaction
+// @Description Cancel a same-day transaction in full and restore product stock
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Param void body models.VoidRequest false "Void payload"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 201 {object} util.JSONResponse{data=models.Refund}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/transactions/{id}/void [post]
+func (h *TransactionHandler) VoidTransaction(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return
+	}
+
+	// Body opsional, berisi alasan void dan metode pengembalian uang
+	var req models.VoidRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "Invalid request body",
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	refund, err := h.service.VoidTransaction(id, req, actorFrom(c))
+	if err != nil {
+		c.JSON(refundErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "transaction voided",
+		Data:    refund,
+	})
+}
+
+// ============================
+// REFUND TRANSACTION
+// ============================
+//
+// RefundTransaction godoc
+// @Summary Refund transaction items
+// @Description Partially refund transaction lines by quantity and restore product stock
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Param refund body models.RefundRequest true "Refund payload"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 201 {object} util.JSONResponse{data=models.Refund}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 422 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/transactions/{id}/refunds [post]
+func (h *TransactionHandler) RefundTransaction(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid id",
+			Data:    nil,
+		})
+		return
+	}
+
+	var req models.RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+		return
+	}
+
+	if len(req.Items) == 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "items tidak boleh kosong",
+			Data:    nil,
+		})
+		return
+	}
+
+	refund, err := h.service.RefundTransaction(id, req, actorFrom(c))
+	if err != nil {
+		c.JSON(refundErrorStatus(err), util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "transaction refunded",
+		Data:    refund,
+	})
+}
+
+// refundErrorStatus memetakan error void/refund ke HTTP status
+func refundErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrTransactionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidRefundItem), errors.Is(err, models.ErrInvalidPayment):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrRefundExceedsSold):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, models.ErrVoidNotAllowed),
+		errors.Is(err, models.ErrTransactionAlreadyVoided),
+		errors.Is(err, models.ErrNothingToRefund):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseTransactionFilter membaca query param filter & pagination daftar transaksi.
+// Default page 1 dan limit 20, limit maksimal 100.
+func parseTransactionFilter(c *gin.Context) (models.TransactionFilter, error) {
+	filter := models.TransactionFilter{Page: 1, Limit: 20}
+
+	intParams := map[string]*int{
+		"page":       &filter.Page,
+		"limit":      &filter.Limit,
+		"product_id": &filter.ProductID,
+	}
+	for name, target := range intParams {
+		if v := c.Query(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return filter, fmt.Errorf("invalid %s", name)
+			}
+			*target = n
+		}
+	}
+
+	amountParams := map[string]**models.Money{
+		"min_amount": &filter.MinAmount,
+		"max_amount": &filter.MaxAmount,
+	}
+	for name, target := range amountParams {
+		if v := c.Query(name); v != "" {
+			amount, err := models.ParseMoney(v)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s", name)
+			}
+			*target = &amount
+		}
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	dateParams := map[string]*string{
+		"start_date": &filter.StartDate,
+		"end_date":   &filter.EndDate,
+	}
+	for name, target := range dateParams {
+		if v := c.Query(name); v != "" {
+			if _, err := time.Parse("2006-01-02", v); err != nil {
+				return filter, fmt.Errorf("invalid %s, gunakan format YYYY-MM-DD", name)
+			}
+			*target = v
+		}
+	}
+
+	return filter, nil
+}
+
 // GetSalesSummary godoc
 // @Summary Get sales summary
-// @Description Get sales summary for today or within a date range if start_date and end_date are provided
+// @Description Get sales summary for today or within a date range if start_date and end_date are provided, including COGS, gross profit and margin per product and per category
 // @Tags transactions
 // @Produce json
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param category_id query int false "Only count products in this category and its sub-categories (payment breakdown is then empty)"
 // @Success 200 {object} util.JSONResponse{data=handler.SalesSummaryResp}
 // @Failure 400 {object} util.JSONResponse
 // @Failure 500 {object} util.JSONResponse
@@ -88,6 +498,20 @@ func (h *TransactionHandler) GetSalesSummary(c *gin.Context) {
 	startDate := c.Query("start_date")
 	endDate := c.Query("end_date")
 
+	// Filter kategori opsional, mencakup seluruh sub-kategorinya
+	categoryID := 0
+	if v := c.Query("category_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: "invalid category_id",
+				Data:    nil,
+			})
+			return
+		}
+		categoryID = n
+	}
+
 	var (
 		summary *util.SalesSummary
 		err     error
@@ -95,10 +519,10 @@ func (h *TransactionHandler) GetSalesSummary(c *gin.Context) {
 
 	// Jika ada rentang tanggal, gunakan summary by range
 	if startDate != "" && endDate != "" {
-		summary, err = h.service.GetSalesSummaryByRange(startDate, endDate)
+		summary, err = h.service.GetSalesSummaryByRange(startDate, endDate, categoryID)
 	} else {
 		// Jika tidak ada params, tampilkan ringkasan hari ini
-		summary, err = h.service.GetSalesSummary()
+		summary, err = h.service.GetSalesSummary(categoryID)
 	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, util.JSONResponse{
@@ -111,9 +535,9 @@ func (h *TransactionHandler) GetSalesSummary(c *gin.Context) {
 	// Produk terlaris mengikuti rentang/harian yang sama
 	var topSellingProduct *models.TopSellingProduct
 	if startDate != "" && endDate != "" {
-		topSellingProduct, err = h.service.GetTopSellingProductByRange(startDate, endDate)
+		topSellingProduct, err = h.service.GetTopSellingProductByRange(startDate, endDate, categoryID)
 	} else {
-		topSellingProduct, err = h.service.GetTopSellingProduct()
+		topSellingProduct, err = h.service.GetTopSellingProduct(categoryID)
 	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, util.JSONResponse{
@@ -125,11 +549,16 @@ func (h *TransactionHandler) GetSalesSummary(c *gin.Context) {
 
 	resp := SalesSummaryResp{
 		TotalRevenue:   summary.TotalRevenue,
+		GrossRevenue:   summary.GrossRevenue,
+		TotalDiscount:  summary.TotalDiscount,
+		TotalRefund:    summary.TotalRefund,
 		TotalTransaksi: summary.TotalTransaksi,
 		ProdukTerlaris: ProdukTerlarisResp{
 			Nama:       topSellingProduct.Name,
 			QtyTerjual: topSellingProduct.QtySold,
 		},
+		Pembayaran: summary.Pembayaran,
+		Profit:     summary.Profit,
 	}
 
 	c.JSON(http.StatusOK, util.JSONResponse{
@@ -137,3 +566,46 @@ func (h *TransactionHandler) GetSalesSummary(c *gin.Context) {
 		Data:    resp,
 	})
 }
+
+// GetTaxReport godoc
+// @Summary Get tax report
+// @Description Get collected tax grouped by tax rate for today or within a date range. Tax refunded within the range is subtracted in net_tax.
+// @Tags transactions
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} util.JSONResponse{data=[]models.TaxReportLine}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/report/tax [get]
+func (h *TransactionHandler) GetTaxReport(c *gin.Context) {
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+
+	for name, v := range map[string]string{"start_date": startDate, "end_date": endDate} {
+		if v == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", v); err != nil {
+			c.JSON(http.StatusBadRequest, util.JSONResponse{
+				Message: fmt.Sprintf("invalid %s, gunakan format YYYY-MM-DD", name),
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	report, err := h.service.GetTaxReport(startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "Tax report",
+		Data:    report,
+	})
+}
@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"simple-crud/models"
+	"simple-crud/service"
+	"simple-crud/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VariantHandler struct {
+	service service.VariantService
+}
+
+func NewVariantHandler(svc service.VariantService) *VariantHandler {
+	return &VariantHandler{service: svc}
+}
+
+// ============================
+// OPTION TYPES
+// ============================
+//
+// GetOptionTypes godoc
+// @Summary Get product option types
+// @Description List the option types (e.g. size, colour) of a product with their values
+// @Tags variants
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} util.JSONResponse{data=[]models.ProductOptionType}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/options [get]
+func (h *VariantHandler) GetOptionTypes(c *gin.Context) {
+	productID, ok := parseVariantParam(c, "id")
+	if !ok {
+		return
+	}
+
+	types, err := h.service.GetOptionTypes(productID)
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "option types retrieved",
+		Data:    types,
+	})
+}
+
+// CreateOptionType godoc
+// @Summary Create product option type
+// @Description Add an option type with its values. Option types can only be added while the product has no variants yet.
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param option body models.OptionTypeRequest true "Option type payload"
+// @Success 201 {object} util.JSONResponse{data=[]models.ProductOptionType}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/options [post]
+func (h *VariantHandler) CreateOptionType(c *gin.Context) {
+	productID, ok := parseVariantParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.OptionTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	types, err := h.service.CreateOptionType(productID, req)
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "option type created",
+		Data:    types,
+	})
+}
+
+// AddOptionValues godoc
+// @Summary Add option values
+// @Description Append values to an existing option type; allowed even when the product already has variants
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param option_id path int true "Option type ID"
+// @Param option body models.OptionTypeRequest true "Values to add (name is ignored)"
+// @Success 200 {object} util.JSONResponse{data=[]models.ProductOptionType}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/options/{option_id}/values [post]
+func (h *VariantHandler) AddOptionValues(c *gin.Context) {
+	productID, ok := parseVariantParam(c, "id")
+	if !ok {
+		return
+	}
+	optionTypeID, ok := parseVariantParam(c, "option_id")
+	if !ok {
+		return
+	}
+
+	var req models.OptionTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	types, err := h.service.AddOptionValues(productID, optionTypeID, req)
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "option values added",
+		Data:    types,
+	})
+}
+
+// DeleteOptionType godoc
+// @Summary Delete product option type
+// @Description Delete an option type and its values; only allowed while the product has no variants
+// @Tags variants
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param option_id path int true "Option type ID"
+// @Success 200 {object} util.JSONResponse
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/options/{option_id} [delete]
+func (h *VariantHandler) DeleteOptionType(c *gin.Context) {
+	productID, ok := parseVariantParam(c, "id")
+	if !ok {
+		return
+	}
+	optionTypeID, ok := parseVariantParam(c, "option_id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteOptionType(productID, optionTypeID); err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "option type deleted",
+		Data:    nil,
+	})
+}
+
+// ============================
+// VARIANTS
+// ============================
+//
+// GetVariants godoc
+// @Summary Get product variants
+// @Description List the variants of a product with their own SKU, price and stock
+// @Tags variants
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} util.JSONResponse{data=[]models.ProductVariant}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/variants [get]
+func (h *VariantHandler) GetVariants(c *gin.Context) {
+	productID, ok := parseVariantParam(c, "id")
+	if !ok {
+		return
+	}
+
+	variants, err := h.service.GetVariants(productID)
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "variants retrieved",
+		Data:    variants,
+	})
+}
+
+// GetVariant godoc
+// @Summary Get product variant
+// @Description Get a single variant of a product
+// @Tags variants
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variant_id path int true "Variant ID"
+// @Success 200 {object} util.JSONResponse{data=models.ProductVariant}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/variants/{variant_id} [get]
+func (h *VariantHandler) GetVariant(c *gin.Context) {
+	productID, ok := parseVariantParam(c, "id")
+	if !ok {
+		return
+	}
+	variantID, ok := parseVariantParam(c, "variant_id")
+	if !ok {
+		return
+	}
+
+	variant, err := h.service.GetVariant(productID, variantID)
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "variant retrieved",
+		Data:    variant,
+	})
+}
+
+// CreateVariant godoc
+// @Summary Create product variant
+// @Description Create a variant with one value for every option type of the product. The parent product stock becomes the sum of its variants; stock held by the product before its first variant is moved out with an adjustment.
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Param variant body models.VariantRequest true "Variant payload"
+// @Success 201 {object} util.JSONResponse{data=models.ProductVariant}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/variants [post]
+func (h *VariantHandler) CreateVariant(c *gin.Context) {
+	productID, ok := parseVariantParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.VariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	variant, err := h.service.CreateVariant(productID, req, actorFrom(c))
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, util.JSONResponse{
+		Message: "variant created",
+		Data:    variant,
+	})
+}
+
+// UpdateVariant godoc
+// @Summary Update product variant
+// @Description Replace the SKU, price, stock and options of a variant. Stock changes are recorded in the stock ledger as adjustments.
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variant_id path int true "Variant ID"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Param variant body models.VariantRequest true "Variant payload"
+// @Success 200 {object} util.JSONResponse{data=models.ProductVariant}
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 409 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/variants/{variant_id} [put]
+func (h *VariantHandler) UpdateVariant(c *gin.Context) {
+	productID, ok := parseVariantParam(c, "id")
+	if !ok {
+		return
+	}
+	variantID, ok := parseVariantParam(c, "variant_id")
+	if !ok {
+		return
+	}
+
+	var req models.VariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	variant, err := h.service.UpdateVariant(productID, variantID, req, actorFrom(c))
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "variant updated",
+		Data:    variant,
+	})
+}
+
+// DeleteVariant godoc
+// @Summary Delete product variant
+// @Description Delete a variant; its remaining stock is removed from the parent product stock
+// @Tags variants
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param variant_id path int true "Variant ID"
+// @Param X-Actor header string false "Nama/ID kasir atau user untuk ledger stok"
+// @Success 200 {object} util.JSONResponse
+// @Failure 400 {object} util.JSONResponse
+// @Failure 404 {object} util.JSONResponse
+// @Failure 500 {object} util.JSONResponse
+// @Router /api/v1/products/{id}/variants/{variant_id} [delete]
+func (h *VariantHandler) DeleteVariant(c *gin.Context) {
+	productID, ok := parseVariantParam(c, "id")
+	if !ok {
+		return
+	}
+	variantID, ok := parseVariantParam(c, "variant_id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteVariant(productID, variantID, actorFrom(c)); err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, util.JSONResponse{
+		Message: "variant deleted",
+		Data:    nil,
+	})
+}
+
+func parseVariantParam(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, util.JSONResponse{
+			Message: "invalid " + name,
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// writeVariantError memetakan error tipe opsi/varian ke HTTP status
+func writeVariantError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrProductNotFound), errors.Is(err, models.ErrVariantNotFound),
+		errors.Is(err, models.ErrOptionTypeNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidVariant), errors.Is(err, models.ErrInvalidSKU):
+		status = http.StatusBadRequest
+	case errors.Is(err, models.ErrDuplicateVariant), errors.Is(err, models.ErrOptionTypeInUse),
+		errors.Is(err, models.ErrDuplicateSKU):
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, util.JSONResponse{
+		Message: err.Error(),
+		Data:    nil,
+	})
+}
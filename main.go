@@ -7,6 +7,7 @@ import (
 	"simple-crud/config"
 	"simple-crud/database"
 	"simple-crud/handler"
+	"simple-crud/models"
 	"simple-crud/repository"
 	"simple-crud/service"
 
@@ -39,10 +40,63 @@ func main() {
 	productService := service.NewProductService(*productRepo)
 	productHandler := handler.NewProductHandler(*productService)
 
+	variantRepo := repository.NewVariantRepository(db)
+	variantService := service.NewVariantService(*variantRepo)
+	variantHandler := handler.NewVariantHandler(*variantService)
+
+	stockMovementRepo := repository.NewStockMovementRepository(db)
+	stockMovementService := service.NewStockMovementService(*stockMovementRepo)
+	stockMovementHandler := handler.NewStockMovementHandler(*stockMovementService)
+
+	stockAlertRepo := repository.NewStockAlertRepository(db)
+	stockAlertService := service.NewStockAlertService(*stockAlertRepo, cfg.LowStockVelocityDays, cfg.LowStockCoverDays)
+	stockAlertHandler := handler.NewStockAlertHandler(*stockAlertService, cfg.StockAlertPollInterval)
+
+	stockTakeRepo := repository.NewStockTakeRepository(db)
+	stockTakeService := service.NewStockTakeService(*stockTakeRepo)
+	stockTakeHandler := handler.NewStockTakeHandler(*stockTakeService)
+
+	supplierRepo := repository.NewSupplierRepository(db)
+	supplierService := service.NewSupplierService(*supplierRepo)
+	supplierHandler := handler.NewSupplierHandler(*supplierService)
+
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db)
+	purchaseOrderService := service.NewPurchaseOrderService(*purchaseOrderRepo)
+	purchaseOrderHandler := handler.NewPurchaseOrderHandler(*purchaseOrderService)
+
+	promotionRepo := repository.NewPromotionRepository(db)
+	promotionService := service.NewPromotionService(*promotionRepo)
+	promotionHandler := handler.NewPromotionHandler(*promotionService)
+
+	taxRateRepo := repository.NewTaxRateRepository(db)
+	taxRateService := service.NewTaxRateService(*taxRateRepo)
+	taxRateHandler := handler.NewTaxRateHandler(*taxRateService)
+
 	transactionRepo := repository.NewTransactionRepository(db)
-	transactionService := service.NewTransactionService(*transactionRepo)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	transactionService := service.NewTransactionService(*transactionRepo, *idempotencyRepo, cfg.IdempotencyTTL)
 	transactionHandler := handler.NewTransactionHandler(*transactionService)
 
+	receiptService, err := service.NewReceiptService(*transactionRepo, models.ReceiptStore{
+		Name:    cfg.StoreName,
+		Address: cfg.StoreAddress,
+		Phone:   cfg.StorePhone,
+		Footer:  cfg.ReceiptFooter,
+	}, cfg.ReceiptTemplatesDir, cfg.ReceiptPaperWidth)
+	if err != nil {
+		log.Fatal("Failed to load receipt templates:", err)
+	}
+	receiptHandler := handler.NewReceiptHandler(*receiptService)
+
+	cartRepo := repository.NewCartRepository(db)
+	cartService := service.NewCartService(*cartRepo, cfg.CartTTL)
+	cartHandler := handler.NewCartHandler(*cartService)
+	cartService.StartCleanup(cfg.CartCleanupInterval)
+
+	purgeRepo := repository.NewPurgeRepository(db)
+	purgeService := service.NewPurgeService(*purgeRepo, cfg.PurgeRetention)
+	purgeService.StartPurge(cfg.PurgeInterval)
+
 	// === Gin Router ===
 	router := gin.Default()
 
@@ -74,27 +128,127 @@ func main() {
 		cat := api.Group("/categories")
 		{
 			cat.GET("", categoryHandler.GetAll)
+			cat.GET("/tree", categoryHandler.GetTree)
+			cat.GET("/export", categoryHandler.Export)
+			cat.POST("/import", categoryHandler.Import)
 			cat.GET("/:id", categoryHandler.GetByID)
 			cat.POST("", categoryHandler.Create)
 			cat.PUT("/:id", categoryHandler.Update)
+			cat.PATCH("/:id", categoryHandler.Patch)
 			cat.DELETE("/:id", categoryHandler.Delete)
+			cat.POST("/:id/restore", categoryHandler.Restore)
 		}
 
 		product := api.Group("/products")
 		{
 			product.GET("", productHandler.GetAll)
+			product.GET("/low-stock", stockAlertHandler.LowStock)
+			product.GET("/lookup", productHandler.Lookup)
+			product.GET("/export", productHandler.Export)
+			product.POST("/import", productHandler.Import)
 			product.GET("/:id", productHandler.GetById)
 			product.POST("", productHandler.Create)
 			product.PUT("/:id", productHandler.Update)
+			product.PATCH("/:id", productHandler.Patch)
 			product.DELETE("/:id", productHandler.Delete)
+			product.POST("/:id/restore", productHandler.Restore)
+			product.GET("/:id/stock-movements", stockMovementHandler.GetByProduct)
+			product.POST("/:id/stock-adjustments", stockMovementHandler.Adjust)
+			product.GET("/:id/options", variantHandler.GetOptionTypes)
+			product.POST("/:id/options", variantHandler.CreateOptionType)
+			product.POST("/:id/options/:option_id/values", variantHandler.AddOptionValues)
+			product.DELETE("/:id/options/:option_id", variantHandler.DeleteOptionType)
+			product.GET("/:id/variants", variantHandler.GetVariants)
+			product.POST("/:id/variants", variantHandler.CreateVariant)
+			product.GET("/:id/variants/:variant_id", variantHandler.GetVariant)
+			product.PUT("/:id/variants/:variant_id", variantHandler.UpdateVariant)
+			product.DELETE("/:id/variants/:variant_id", variantHandler.DeleteVariant)
+		}
+
+		promotion := api.Group("/promotions")
+		{
+			promotion.GET("", promotionHandler.GetAll)
+			promotion.GET("/:id", promotionHandler.GetByID)
+			promotion.POST("", promotionHandler.Create)
+			promotion.PUT("/:id", promotionHandler.Update)
+			promotion.DELETE("/:id", promotionHandler.Delete)
+		}
+
+		taxRate := api.Group("/tax-rates")
+		{
+			taxRate.GET("", taxRateHandler.GetAll)
+			taxRate.GET("/:id", taxRateHandler.GetByID)
+			taxRate.POST("", taxRateHandler.Create)
+			taxRate.PUT("/:id", taxRateHandler.Update)
+			taxRate.DELETE("/:id", taxRateHandler.Delete)
 		}
 
 		api.POST("/checkout", transactionHandler.Checkout)
 
+		transaction := api.Group("/transactions")
+		{
+			transaction.GET("", transactionHandler.GetTransactions)
+			transaction.GET("/:id", transactionHandler.GetTransactionByID)
+			transaction.POST("/:id/void", transactionHandler.VoidTransaction)
+			transaction.POST("/:id/refunds", transactionHandler.RefundTransaction)
+			transaction.GET("/:id/receipt", receiptHandler.GetReceipt)
+		}
+
+		cart := api.Group("/carts")
+		{
+			cart.GET("", cartHandler.GetAll)
+			cart.POST("", cartHandler.Create)
+			cart.GET("/:id", cartHandler.GetByID)
+			cart.DELETE("/:id", cartHandler.Delete)
+			cart.POST("/:id/items", cartHandler.AddItem)
+			cart.PUT("/:id/items/:product_id", cartHandler.UpdateItem)
+			cart.DELETE("/:id/items/:product_id", cartHandler.RemoveItem)
+			cart.POST("/:id/hold", cartHandler.Hold)
+			cart.POST("/:id/resume", cartHandler.Resume)
+			cart.POST("/:id/checkout", cartHandler.Checkout)
+		}
+
+		stockAlert := api.Group("/stock-alerts")
+		{
+			stockAlert.GET("", stockAlertHandler.GetAll)
+			stockAlert.GET("/stream", stockAlertHandler.Stream)
+		}
+
+		stockTake := api.Group("/stock-takes")
+		{
+			stockTake.GET("", stockTakeHandler.GetAll)
+			stockTake.POST("", stockTakeHandler.Start)
+			stockTake.GET("/:id", stockTakeHandler.GetByID)
+			stockTake.PUT("/:id/items", stockTakeHandler.SubmitCounts)
+			stockTake.POST("/:id/commit", stockTakeHandler.Commit)
+			stockTake.POST("/:id/cancel", stockTakeHandler.Cancel)
+		}
+
+		supplier := api.Group("/suppliers")
+		{
+			supplier.GET("", supplierHandler.GetAll)
+			supplier.POST("", supplierHandler.Create)
+			supplier.GET("/:id", supplierHandler.GetByID)
+			supplier.PUT("/:id", supplierHandler.Update)
+			supplier.DELETE("/:id", supplierHandler.Delete)
+		}
+
+		purchaseOrder := api.Group("/purchase-orders")
+		{
+			purchaseOrder.GET("", purchaseOrderHandler.GetAll)
+			purchaseOrder.POST("", purchaseOrderHandler.Create)
+			purchaseOrder.GET("/:id", purchaseOrderHandler.GetByID)
+			purchaseOrder.PUT("/:id", purchaseOrderHandler.Update)
+			purchaseOrder.POST("/:id/send", purchaseOrderHandler.Send)
+			purchaseOrder.POST("/:id/cancel", purchaseOrderHandler.Cancel)
+			purchaseOrder.POST("/:id/receive", purchaseOrderHandler.Receive)
+		}
+
 		report := api.Group("/report")
 		{
 			report.GET("/hari-ini", transactionHandler.GetSalesSummary)
 			report.GET("", transactionHandler.GetSalesSummary)
+			report.GET("/tax", transactionHandler.GetTaxReport)
 		}
 
 	}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidBarcode dikembalikan jika barcode bukan EAN-8, UPC-A atau EAN-13 dengan check digit yang benar
+	ErrInvalidBarcode = errors.New("barcode tidak valid")
+	// ErrInvalidSKU dikembalikan jika SKU terlalu panjang atau mengandung spasi
+	ErrInvalidSKU = errors.New("sku tidak valid")
+	// ErrDuplicateSKU dikembalikan jika SKU sudah dipakai produk lain
+	ErrDuplicateSKU = errors.New("sku sudah dipakai produk lain")
+	// ErrDuplicateBarcode dikembalikan jika barcode sudah terdaftar di produk lain
+	ErrDuplicateBarcode = errors.New("barcode sudah terdaftar di produk lain")
+)
+
+const maxSKULength = 64
+
+// ValidateBarcode memeriksa panjang (8, 12 atau 13 digit) dan check digit GS1 barcode
+func ValidateBarcode(code string) error {
+	switch len(code) {
+	case 8, 12, 13:
+	default:
+		return fmt.Errorf("%w: %q harus 8 (EAN-8), 12 (UPC-A) atau 13 (EAN-13) digit", ErrInvalidBarcode, code)
+	}
+
+	sum := 0
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return fmt.Errorf("%w: %q hanya boleh berisi angka", ErrInvalidBarcode, code)
+		}
+	}
+	// Bobot 3 dan 1 bergantian mulai dari digit tepat sebelum check digit (dari kanan)
+	for i := len(code) - 2; i >= 0; i-- {
+		digit := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	if check := (10 - sum%10) % 10; int(code[len(code)-1]-'0') != check {
+		return fmt.Errorf("%w: check digit %q salah, seharusnya %d", ErrInvalidBarcode, code, check)
+	}
+	return nil
+}
+
+// NormalizeCodes merapikan SKU dan barcode produk sebelum disimpan: spasi dibuang,
+// barcode duplikat di payload digabung, dan setiap barcode divalidasi check digit-nya.
+func (p *Product) NormalizeCodes() error {
+	p.SKU = strings.TrimSpace(p.SKU)
+	if len(p.SKU) > maxSKULength || strings.ContainsAny(p.SKU, " \t\n") {
+		return fmt.Errorf("%w: maksimal %d karakter tanpa spasi", ErrInvalidSKU, maxSKULength)
+	}
+
+	seen := make(map[string]bool, len(p.Barcodes))
+	barcodes := make([]string, 0, len(p.Barcodes))
+	for _, code := range p.Barcodes {
+		code = strings.TrimSpace(code)
+		if seen[code] {
+			continue
+		}
+		if err := ValidateBarcode(code); err != nil {
+			return err
+		}
+		seen[code] = true
+		barcodes = append(barcodes, code)
+	}
+	p.Barcodes = barcodes
+	return nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// CartStatusOpen adalah keranjang yang sedang dilayani kasir dan bisa diubah
+	CartStatusOpen = "open"
+	// CartStatusHeld adalah keranjang yang diparkir; harus di-resume sebelum diubah atau di-checkout
+	CartStatusHeld = "held"
+	// CartStatusCheckedOut adalah keranjang yang sudah menjadi transaksi
+	CartStatusCheckedOut = "checked_out"
+)
+
+var (
+	// ErrCartNotFound dikembalikan jika keranjang dengan id tertentu tidak ada (atau sudah dibersihkan karena kedaluwarsa)
+	ErrCartNotFound = errors.New("keranjang tidak ditemukan")
+	// ErrCartNotOpen dikembalikan jika keranjang yang sedang di-hold atau sudah di-checkout diubah
+	ErrCartNotOpen = errors.New("keranjang tidak dalam status open")
+	// ErrCartNotHeld dikembalikan jika resume dipanggil untuk keranjang yang tidak di-hold
+	ErrCartNotHeld = errors.New("keranjang tidak dalam status held")
+	// ErrCartEmpty dikembalikan jika keranjang tanpa item di-checkout
+	ErrCartEmpty = errors.New("keranjang kosong")
+	// ErrCartItemNotFound dikembalikan jika produk tidak ada di keranjang
+	ErrCartItemNotFound = errors.New("produk tidak ada di keranjang")
+	// ErrProductNotFound dikembalikan jika produk yang ditambahkan ke keranjang tidak ada
+	ErrProductNotFound = errors.New("produk tidak ditemukan")
+)
+
+// Cart adalah keranjang di sisi server yang bisa diparkir (hold) lalu dilanjutkan (resume).
+// Harga dan stok setiap item selalu dibaca ulang dari produk saat keranjang ditampilkan.
+type Cart struct {
+	ID            int        `json:"id"`
+	Name          string     `json:"name"` // label keranjang, mis. nama pelanggan saat di-hold
+	Status        string     `json:"status" enums:"open,held,checked_out"`
+	TransactionID *int       `json:"transaction_id"`
+	Items         []CartItem `json:"items"`
+	Subtotal      Money      `json:"subtotal" swaggertype:"number"` // harga saat ini x quantity, sebelum promo dan pajak
+	StockOK       bool       `json:"stock_ok"`                      // false jika ada item yang melebihi stok saat ini
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+}
+
+// CartItem adalah satu produk (atau satu varian) di keranjang beserta harga dan stok terkini
+type CartItem struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	VariantID   *int   `json:"variant_id,omitempty"`
+	VariantName string `json:"variant_name,omitempty"`
+	UnitPrice   Money  `json:"unit_price" swaggertype:"number"`
+	Quantity    int    `json:"quantity"`
+	Subtotal    Money  `json:"subtotal" swaggertype:"number"`
+	Stock       int    `json:"stock"` // stok varian untuk item bervarian
+	StockOK     bool   `json:"stock_ok"`
+}
+
+// CreateCartRequest adalah payload membuat keranjang
+type CreateCartRequest struct {
+	Name string `json:"name"`
+}
+
+// CartItemRequest adalah payload menambah/mengubah item keranjang
+type CartItemRequest struct {
+	ProductID int `json:"product_id"`
+	VariantID int `json:"variant_id,omitempty"` // wajib untuk produk bervarian
+	Quantity  int `json:"quantity"`
+}
+
+// HoldCartRequest adalah payload memarkir keranjang
+type HoldCartRequest struct {
+	Name string `json:"name"`
+}
+
+// CartCheckoutRequest adalah payload checkout keranjang; item diambil dari keranjang
+type CartCheckoutRequest struct {
+	VoucherCodes []string          `json:"voucher_codes,omitempty"`
+	Payments     []CheckoutPayment `json:"payments,omitempty"`
+}
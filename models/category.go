@@ -1,7 +1,116 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrCategoryNotFound dikembalikan jika kategori tidak ada
+	ErrCategoryNotFound = errors.New("Kategori tidak ditemukan")
+	// ErrInvalidCategoryParent dikembalikan jika parent_id tidak ada, sama dengan kategori itu sendiri, atau membentuk siklus
+	ErrInvalidCategoryParent = errors.New("parent kategori tidak valid")
+	// ErrCategoryHasChildren dikembalikan saat menghapus kategori yang masih memiliki sub-kategori tanpa mode reparent
+	ErrCategoryHasChildren = errors.New("kategori masih memiliki sub-kategori")
+	// ErrInvalidCategoryDeleteMode dikembalikan jika mode penanganan sub-kategori saat hapus tidak dikenal
+	ErrInvalidCategoryDeleteMode = errors.New("children harus refuse atau reparent")
+	// ErrInvalidCategoryReassign dikembalikan jika kategori tujuan reassign_to tidak ada atau sama dengan kategori yang dihapus
+	ErrInvalidCategoryReassign = errors.New("kategori tujuan reassign_to tidak valid")
+	// ErrCategoryNotDeleted dikembalikan jika restore dipanggil untuk kategori yang tidak sedang dihapus
+	ErrCategoryNotDeleted = errors.New("kategori tidak dalam keadaan terhapus")
+	// ErrCategoryDeleted dikembalikan jika produk atau sub-kategori dipasang/di-restore ke kategori yang sudah dihapus
+	ErrCategoryDeleted = errors.New("kategori sudah dihapus")
+	// ErrInvalidCategory dikembalikan jika data kategori hasil PATCH tidak valid
+	ErrInvalidCategory = errors.New("kategori tidak valid")
+)
+
+const (
+	// CategoryChildrenRefuse menolak hapus kategori yang masih memiliki sub-kategori (default)
+	CategoryChildrenRefuse = "refuse"
+	// CategoryChildrenReparent memindahkan sub-kategori ke parent dari kategori yang dihapus
+	CategoryChildrenReparent = "reparent"
+)
+
 type Category struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	TaxRateID   *int       `json:"tax_rate_id"`
+	ParentID    *int       `json:"parent_id"`            // null untuk kategori level teratas
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // terisi jika kategori sudah dihapus (soft delete)
+	Version     int        `json:"version"`              // naik setiap perubahan; dikirim sebagai ETag
+}
+
+// Validate memeriksa field wajib kategori
+func (c *Category) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return fmt.Errorf("%w: name wajib diisi", ErrInvalidCategory)
+	}
+	if len(c.Name) > 255 {
+		return fmt.Errorf("%w: name maksimal 255 karakter", ErrInvalidCategory)
+	}
+	return nil
+}
+
+// CategoryDeleteOptions mengatur penanganan data yang masih merujuk kategori saat dihapus
+type CategoryDeleteOptions struct {
+	Children   string // refuse (default) atau reparent
+	ReassignTo *int   // kategori tujuan untuk produk yang masih memakai kategori ini
+}
+
+// CategoryDeleteResult adalah hasil hapus kategori
+type CategoryDeleteResult struct {
+	ID                 int `json:"id"`
+	ReassignedProducts int `json:"reassigned_products"` // jumlah produk yang dipindah ke reassign_to
+}
+
+// CategoryUsage adalah jumlah data yang masih merujuk kategori
+type CategoryUsage struct {
+	ProductCount int `json:"product_count"`
+}
+
+// ErrCategoryInUse dikembalikan saat menghapus kategori yang masih dipakai produk tanpa reassign_to
+type ErrCategoryInUse struct {
+	Usage CategoryUsage
+}
+
+func (e *ErrCategoryInUse) Error() string {
+	return fmt.Sprintf("kategori masih dipakai %d produk, gunakan reassign_to untuk memindahkannya", e.Usage.ProductCount)
+}
+
+// CategoryNode adalah kategori beserta seluruh sub-kategorinya
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+}
+
+// BuildCategoryTree menyusun daftar kategori (sudah terurut) menjadi pohon. Urutan anak mengikuti urutan input.
+func BuildCategoryTree(categories []Category) []CategoryNode {
+	ids := make(map[int]bool, len(categories))
+	for _, c := range categories {
+		ids[c.ID] = true
+	}
+
+	childrenOf := make(map[int][]Category)
+	roots := make([]Category, 0)
+	for _, c := range categories {
+		if c.ParentID == nil || !ids[*c.ParentID] {
+			roots = append(roots, c)
+			continue
+		}
+		childrenOf[*c.ParentID] = append(childrenOf[*c.ParentID], c)
+	}
+
+	var build func(list []Category) []CategoryNode
+	build = func(list []Category) []CategoryNode {
+		nodes := make([]CategoryNode, 0, len(list))
+		for _, c := range list {
+			nodes = append(nodes, CategoryNode{Category: c, Children: build(childrenOf[c.ID])})
+		}
+		return nodes
+	}
+	return build(roots)
 }
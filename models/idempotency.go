@@ -0,0 +1,22 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyMismatch dikembalikan jika Idempotency-Key dipakai ulang dengan body request yang berbeda
+	ErrIdempotencyKeyMismatch = errors.New("Idempotency-Key sudah dipakai untuk request dengan body berbeda")
+	// ErrIdempotencyKeyInProgress dikembalikan jika request dengan Idempotency-Key yang sama masih diproses
+	ErrIdempotencyKeyInProgress = errors.New("request dengan Idempotency-Key yang sama sedang diproses")
+)
+
+type IdempotencyKey struct {
+	Key           string    `json:"key"`
+	RequestHash   string    `json:"request_hash"`
+	TransactionID *int      `json:"transaction_id"`
+	ResponseBody  []byte    `json:"response_body"`
+	CreatedAt     time.Time `json:"created_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
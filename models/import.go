@@ -0,0 +1,328 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	SpreadsheetFormatCSV  = "csv"
+	SpreadsheetFormatXLSX = "xlsx"
+
+	ImportModeDryRun = "dry_run"
+	ImportModeCommit = "commit"
+
+	// MaxImportRows membatasi jumlah baris data per file import
+	MaxImportRows = 50000
+)
+
+var (
+	// ErrUnsupportedSpreadsheetFormat dikembalikan jika format file bukan csv atau xlsx
+	ErrUnsupportedSpreadsheetFormat = errors.New("format file harus csv atau xlsx")
+	// ErrInvalidImport dikembalikan jika file import tidak bisa dibaca sama sekali (header hilang, file kosong, terlalu besar)
+	ErrInvalidImport = errors.New("file import tidak valid")
+	// ErrInvalidImportMode dikembalikan jika mode import bukan dry_run atau commit
+	ErrInvalidImportMode = errors.New("mode import harus dry_run atau commit")
+)
+
+// ImportRowError adalah satu kesalahan pada baris file import. Row adalah nomor baris di spreadsheet (header = 1).
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportReport adalah hasil import. Pada dry_run, Created/Updated adalah jumlah yang akan dibuat/diubah.
+// Import commit bersifat all-or-nothing: jika ada satu saja baris gagal, tidak ada yang disimpan.
+type ImportReport struct {
+	Mode      string           `json:"mode"`
+	Committed bool             `json:"committed"`
+	TotalRows int              `json:"total_rows"`
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Failed    int              `json:"failed"` // jumlah baris yang memiliki error
+	Errors    []ImportRowError `json:"errors"`
+}
+
+// AddError mencatat error untuk satu baris
+func (r *ImportReport) AddError(row int, column, message string) {
+	r.Errors = append(r.Errors, ImportRowError{Row: row, Column: column, Message: message})
+}
+
+// Finish menghitung jumlah baris gagal; satu baris bisa punya beberapa error
+func (r *ImportReport) Finish() {
+	rows := make(map[int]bool)
+	for _, e := range r.Errors {
+		rows[e.Row] = true
+	}
+	r.Failed = len(rows)
+}
+
+// ProductExportColumns adalah kolom export produk; file hasil export bisa langsung di-import kembali
+var ProductExportColumns = []string{"sku", "name", "category_id", "category", "price", "stock", "cost_price",
+	"min_stock", "reorder_qty", "tax_rate_id", "barcodes"}
+
+// CategoryExportColumns adalah kolom export kategori
+var CategoryExportColumns = []string{"id", "name", "description", "tax_rate_id"}
+
+// ProductImportRow adalah satu baris import produk. Field nil berarti sel kosong atau kolom tidak ada:
+// produk yang sudah ada mempertahankan nilai lamanya, produk baru memakai nilai default.
+type ProductImportRow struct {
+	Row          int
+	SKU          string
+	Name         *string
+	CategoryID   int    // 0 jika tidak diisi
+	CategoryName string // dipakai jika category_id kosong
+	Price        *Money
+	Stock        *int
+	CostPrice    *Money
+	MinStock     *int
+	ReorderQty   *int
+	TaxRateID    *int
+	Barcodes     []string // nil berarti barcode produk tidak diubah
+}
+
+// CategoryImportRow adalah satu baris import kategori; dicocokkan dengan id, lalu dengan nama
+type CategoryImportRow struct {
+	Row         int
+	ID          int
+	Name        string
+	Description *string
+	TaxRateID   *int
+}
+
+// importTable membaca header (baris pertama) dan memberi akses sel per nama kolom
+type importTable struct {
+	columns map[string]int
+	rows    [][]string
+}
+
+var importColumnAliases = map[string]string{
+	"category_name": "category",
+	"barcode":       "barcodes",
+}
+
+func newImportTable(table [][]string, known []string) (*importTable, error) {
+	if len(table) == 0 {
+		return nil, fmt.Errorf("%w: file kosong", ErrInvalidImport)
+	}
+	if len(table)-1 > MaxImportRows {
+		return nil, fmt.Errorf("%w: maksimal %d baris data", ErrInvalidImport, MaxImportRows)
+	}
+
+	t := &importTable{columns: make(map[string]int), rows: table[1:]}
+	for i, name := range table[0] {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if alias, ok := importColumnAliases[name]; ok {
+			name = alias
+		}
+		for _, k := range known {
+			if name == k {
+				if _, dup := t.columns[name]; dup {
+					return nil, fmt.Errorf("%w: kolom %s muncul lebih dari sekali", ErrInvalidImport, name)
+				}
+				t.columns[name] = i
+			}
+		}
+	}
+	return t, nil
+}
+
+func (t *importTable) has(column string) bool {
+	_, ok := t.columns[column]
+	return ok
+}
+
+func (t *importTable) cell(row []string, column string) string {
+	i, ok := t.columns[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// rowParser mengumpulkan error parsing sel untuk satu baris
+type rowParser struct {
+	table  *importTable
+	values []string
+	row    int
+	report *ImportReport
+}
+
+func (p *rowParser) str(column string) string {
+	return p.table.cell(p.values, column)
+}
+
+func (p *rowParser) optionalString(column string) *string {
+	if !p.table.has(column) {
+		return nil
+	}
+	v := p.str(column)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func (p *rowParser) optionalInt(column string, min int) *int {
+	v := p.str(column)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min {
+		p.report.AddError(p.row, column, fmt.Sprintf("harus bilangan bulat >= %d", min))
+		return nil
+	}
+	return &n
+}
+
+func (p *rowParser) optionalMoney(column string) *Money {
+	v := p.str(column)
+	if v == "" {
+		return nil
+	}
+	m, err := ParseMoney(v)
+	if err != nil || m.IsNegative() {
+		p.report.AddError(p.row, column, "harus angka >= 0")
+		return nil
+	}
+	return &m
+}
+
+// ParseProductImport mengubah isi spreadsheet menjadi baris import produk. Error per sel dan duplikasi
+// SKU/barcode di dalam file dicatat di report; baris yang error tidak dikembalikan.
+func ParseProductImport(table [][]string, report *ImportReport) ([]ProductImportRow, error) {
+	t, err := newImportTable(table, ProductExportColumns)
+	if err != nil {
+		return nil, err
+	}
+	if !t.has("sku") {
+		return nil, fmt.Errorf("%w: kolom sku wajib ada", ErrInvalidImport)
+	}
+
+	rows := make([]ProductImportRow, 0, len(t.rows))
+	skuRows := make(map[string]int)
+	barcodeRows := make(map[string]int)
+	for i, values := range t.rows {
+		if isBlankRow(values) {
+			continue
+		}
+		report.TotalRows++
+
+		p := &rowParser{table: t, values: values, row: i + 2, report: report}
+		errorsBefore := len(report.Errors)
+
+		row := ProductImportRow{
+			Row:          p.row,
+			SKU:          p.str("sku"),
+			Name:         p.optionalString("name"),
+			CategoryName: p.str("category"),
+			Price:        p.optionalMoney("price"),
+			Stock:        p.optionalInt("stock", 0),
+			CostPrice:    p.optionalMoney("cost_price"),
+			MinStock:     p.optionalInt("min_stock", 0),
+			ReorderQty:   p.optionalInt("reorder_qty", 0),
+			TaxRateID:    p.optionalInt("tax_rate_id", 1),
+		}
+		if id := p.optionalInt("category_id", 1); id != nil {
+			row.CategoryID = *id
+		}
+
+		switch {
+		case row.SKU == "":
+			report.AddError(p.row, "sku", "sku wajib diisi")
+		case len(row.SKU) > maxSKULength || strings.ContainsAny(row.SKU, " \t\n"):
+			report.AddError(p.row, "sku", fmt.Sprintf("maksimal %d karakter tanpa spasi", maxSKULength))
+		case skuRows[row.SKU] > 0:
+			report.AddError(p.row, "sku", fmt.Sprintf("sku %s sudah ada di baris %d", row.SKU, skuRows[row.SKU]))
+		default:
+			skuRows[row.SKU] = p.row
+		}
+
+		if row.Name != nil && len(*row.Name) > 255 {
+			report.AddError(p.row, "name", "maksimal 255 karakter")
+		}
+
+		if v := p.str("barcodes"); v != "" {
+			product := Product{Barcodes: strings.FieldsFunc(v, func(r rune) bool {
+				return r == ';' || r == ',' || r == ' ' || r == '|'
+			})}
+			if err := product.NormalizeCodes(); err != nil {
+				report.AddError(p.row, "barcodes", err.Error())
+			}
+			for _, code := range product.Barcodes {
+				if other := barcodeRows[code]; other > 0 {
+					report.AddError(p.row, "barcodes", fmt.Sprintf("barcode %s sudah ada di baris %d", code, other))
+				} else {
+					barcodeRows[code] = p.row
+				}
+			}
+			row.Barcodes = product.Barcodes
+		}
+
+		if len(report.Errors) == errorsBefore {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// ParseCategoryImport mengubah isi spreadsheet menjadi baris import kategori
+func ParseCategoryImport(table [][]string, report *ImportReport) ([]CategoryImportRow, error) {
+	t, err := newImportTable(table, CategoryExportColumns)
+	if err != nil {
+		return nil, err
+	}
+	if !t.has("id") && !t.has("name") {
+		return nil, fmt.Errorf("%w: kolom id atau name wajib ada", ErrInvalidImport)
+	}
+
+	rows := make([]CategoryImportRow, 0, len(t.rows))
+	idRows := make(map[int]int)
+	for i, values := range t.rows {
+		if isBlankRow(values) {
+			continue
+		}
+		report.TotalRows++
+
+		p := &rowParser{table: t, values: values, row: i + 2, report: report}
+		errorsBefore := len(report.Errors)
+
+		row := CategoryImportRow{
+			Row:         p.row,
+			Name:        p.str("name"),
+			Description: p.optionalString("description"),
+			TaxRateID:   p.optionalInt("tax_rate_id", 1),
+		}
+		if id := p.optionalInt("id", 1); id != nil {
+			row.ID = *id
+			if other := idRows[row.ID]; other > 0 {
+				report.AddError(p.row, "id", fmt.Sprintf("id %d sudah ada di baris %d", row.ID, other))
+			}
+			idRows[row.ID] = p.row
+		}
+		if row.ID == 0 && row.Name == "" && p.str("id") == "" {
+			report.AddError(p.row, "name", "name wajib diisi untuk kategori baru")
+		}
+		if len(row.Name) > 255 {
+			report.AddError(p.row, "name", "maksimal 255 karakter")
+		}
+
+		if len(report.Errors) == errorsBefore {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
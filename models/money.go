@@ -0,0 +1,325 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultCurrency adalah mata uang default Money dan mata uang semua kolom uang di database
+	DefaultCurrency = "IDR"
+	// MinorUnitScale adalah jumlah minor unit (sen) dalam satu major unit (rupiah)
+	MinorUnitScale = 100
+	// minorUnitDigits adalah jumlah digit desimal yang disimpan
+	minorUnitDigits = 2
+	// maxMajorUnitDigits adalah jumlah digit bulat maksimal yang muat di kolom NUMERIC(14,2)
+	maxMajorUnitDigits = 12
+	// maxScanMajorUnitDigits menjaga major*MinorUnitScale tetap muat di int64 saat membaca agregat database
+	maxScanMajorUnitDigits = 16
+)
+
+var (
+	ErrInvalidMoney = errors.New("format nominal uang tidak valid")
+	// ErrUnsupportedCurrency dikembalikan jika nominal dikirim dengan mata uang yang tidak didukung
+	ErrUnsupportedCurrency = errors.New("mata uang tidak didukung")
+	// ErrCurrencyMismatch dipakai saat dua nilai uang dengan mata uang berbeda dijumlahkan atau dibandingkan
+	ErrCurrencyMismatch = errors.New("mata uang berbeda")
+)
+
+// supportedCurrencies adalah mata uang yang diterima dari JSON. Semua kolom uang disimpan dalam
+// DefaultCurrency, jadi mata uang lain baru bisa ditambahkan bersama konversi dan kolom mata uang.
+var supportedCurrencies = map[string]bool{
+	DefaultCurrency: true,
+}
+
+// Money adalah nilai uang fixed-point dalam minor unit (1/100 rupiah).
+//
+// Aturan pembulatan: setiap konversi yang menghasilkan pecahan di bawah minor unit
+// (parsing angka dengan lebih dari 2 desimal, MulRatio, Percent) dibulatkan
+// half away from zero, mis. 0.005 -> 0.01 dan -0.005 -> -0.01.
+//
+// Currency kosong (zero value) berarti DefaultCurrency. Operasi aritmetika dan perbandingan antara
+// dua mata uang berbeda adalah bug pemanggil dan menyebabkan panic dengan ErrCurrencyMismatch.
+//
+// Di JSON, Money ditulis sebagai angka dalam major unit dengan 2 desimal (mis. 12500.50),
+// dan di database disimpan sebagai NUMERIC(14,2) dalam DefaultCurrency.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney membuat Money dari minor unit dengan mata uang default
+func NewMoney(minor int64) Money {
+	return Money{Amount: minor, Currency: DefaultCurrency}
+}
+
+// MoneyFromMajor membuat Money dari nilai major unit (rupiah utuh)
+func MoneyFromMajor(major int64) Money {
+	return NewMoney(major * MinorUnitScale)
+}
+
+// ParseMoney mengubah string desimal (mis. "12500.5", "-3", "1.005") menjadi Money.
+// Digit di belakang 2 desimal dibulatkan half away from zero. Nilai dengan lebih dari 12 digit
+// bulat ditolak karena tidak muat di kolom NUMERIC(14,2).
+func ParseMoney(s string) (Money, error) {
+	return parseMoney(s, maxMajorUnitDigits)
+}
+
+// parseMoney adalah ParseMoney dengan batas digit bulat; hasil agregat dari database (mis. SUM)
+// boleh melebihi NUMERIC(14,2) sehingga Scan memakai batas int64
+func parseMoney(s string, maxIntDigits int) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Money{}, ErrInvalidMoney
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return Money{}, ErrInvalidMoney
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+
+	roundUp := false
+	if len(fracPart) > minorUnitDigits {
+		roundUp = fracPart[minorUnitDigits] >= '5'
+		fracPart = fracPart[:minorUnitDigits]
+	}
+	for len(fracPart) < minorUnitDigits {
+		fracPart += "0"
+	}
+
+	if len(strings.TrimLeft(intPart, "0")) > maxIntDigits {
+		return Money{}, fmt.Errorf("%w: maksimal %d digit sebelum desimal", ErrInvalidMoney, maxIntDigits)
+	}
+	major, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	minor, _ := strconv.ParseInt(fracPart, 10, 64)
+
+	amount := major*MinorUnitScale + minor
+	if roundUp {
+		amount++
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return NewMoney(amount), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add menjumlahkan dua nilai uang dengan mata uang yang sama
+func (m Money) Add(other Money) Money {
+	return Money{Amount: m.Amount + other.Amount, Currency: m.sameCurrency(other)}
+}
+
+// Sub mengurangkan other dari m; keduanya harus bermata uang sama
+func (m Money) Sub(other Money) Money {
+	return Money{Amount: m.Amount - other.Amount, Currency: m.sameCurrency(other)}
+}
+
+// Mul mengalikan dengan bilangan bulat (mis. quantity), selalu eksak
+func (m Money) Mul(n int64) Money {
+	return Money{Amount: m.Amount * n, Currency: m.CurrencyCode()}
+}
+
+// MulRatio mengalikan dengan num/den, dibulatkan half away from zero ke minor unit
+func (m Money) MulRatio(num, den int64) Money {
+	return Money{Amount: divRoundHalfAway(m.Amount*num, den), Currency: m.CurrencyCode()}
+}
+
+// CurrencyCode mengembalikan mata uang m; zero value berarti DefaultCurrency
+func (m Money) CurrencyCode() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// sameCurrency mengembalikan mata uang bersama m dan other, atau panic jika berbeda
+func (m Money) sameCurrency(other Money) string {
+	if m.CurrencyCode() != other.CurrencyCode() {
+		panic(fmt.Errorf("%w: %s dan %s", ErrCurrencyMismatch, m.CurrencyCode(), other.CurrencyCode()))
+	}
+	return m.CurrencyCode()
+}
+
+// Percent menghitung basisPoints/10000 dari nilai (mis. 1100 = 11%), dibulatkan half away from zero
+func (m Money) Percent(basisPoints int64) Money {
+	return m.MulRatio(basisPoints, 10000)
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Cmp mengembalikan -1, 0, atau 1 jika m lebih kecil, sama, atau lebih besar dari other
+func (m Money) Cmp(other Money) int {
+	m.sameCurrency(other)
+	switch {
+	case m.Amount < other.Amount:
+		return -1
+	case m.Amount > other.Amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// String mengembalikan nilai dalam major unit dengan 2 desimal, mis. "12500.50"
+func (m Money) String() string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/MinorUnitScale, amount%MinorUnitScale)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON menerima angka (12500.5), string ("12500.5") atau objek dengan mata uang
+// ({"amount": 12500.5, "currency": "IDR"}). Mata uang yang tidak didukung ditolak dengan ErrUnsupportedCurrency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, "{") {
+		var v struct {
+			Amount   *json.RawMessage `json:"amount"`
+			Currency string           `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if v.Amount == nil || strings.HasPrefix(strings.TrimSpace(string(*v.Amount)), "{") {
+			return fmt.Errorf("%w: amount wajib berupa angka", ErrInvalidMoney)
+		}
+		currency := strings.ToUpper(strings.TrimSpace(v.Currency))
+		if currency == "" {
+			currency = DefaultCurrency
+		}
+		if !supportedCurrencies[currency] {
+			return fmt.Errorf("%w: %s", ErrUnsupportedCurrency, v.Currency)
+		}
+		var parsed Money
+		if err := parsed.UnmarshalJSON(*v.Amount); err != nil {
+			return err
+		}
+		parsed.Currency = currency
+		*m = parsed
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan mengimplementasikan sql.Scanner untuk kolom NUMERIC/INT
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*m = NewMoney(0)
+		return nil
+	case int64:
+		*m = MoneyFromMajor(v)
+		return nil
+	case float64:
+		parsed, err := parseMoney(strconv.FormatFloat(v, 'f', -1, 64), maxScanMajorUnitDigits)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
+
+func (m *Money) scanString(s string) error {
+	parsed, err := parseMoney(s, maxScanMajorUnitDigits)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value mengimplementasikan driver.Valuer, disimpan sebagai string desimal untuk kolom NUMERIC.
+// Kolom uang tidak menyimpan mata uang, jadi hanya DefaultCurrency yang boleh ditulis.
+func (m Money) Value() (driver.Value, error) {
+	if m.CurrencyCode() != DefaultCurrency {
+		return nil, fmt.Errorf("%w: kolom uang hanya menyimpan %s, bukan %s", ErrCurrencyMismatch, DefaultCurrency, m.CurrencyCode())
+	}
+	return m.String(), nil
+}
+
+// divRoundHalfAway membagi a dengan b dan membulatkan half away from zero
+func divRoundHalfAway(a, b int64) int64 {
+	if b == 0 {
+		return 0
+	}
+	if b < 0 {
+		a, b = -a, -b
+	}
+	q := a / b
+	r := a % b
+	if r < 0 {
+		r = -r
+	}
+	if r*2 >= b {
+		if a < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{name: "integer", input: "12500", want: 1250000},
+		{name: "one decimal", input: "12500.5", want: 1250050},
+		{name: "two decimals", input: "0.01", want: 1},
+		{name: "leading dot", input: ".5", want: 50},
+		{name: "plus sign", input: "+2", want: 200},
+		{name: "negative integer", input: "-3", want: -300},
+		{name: "surrounding spaces", input: " 7.25 ", want: 725},
+		{name: "half rounds up", input: "1.005", want: 101},
+		{name: "below half rounds down", input: "1.004", want: 100},
+		{name: "round carries to major unit", input: "0.999", want: 100},
+		{name: "negative half rounds away from zero", input: "-0.005", want: -1},
+		{name: "negative below half", input: "-0.004", want: 0},
+		{name: "only third digit counts", input: "2.0049999", want: 200},
+		{name: "max NUMERIC(14,2)", input: "999999999999.99", want: 99999999999999},
+		{name: "leading zeros are not digits", input: "0000999999999999", want: 99999999999900},
+		{name: "too many integer digits", input: "1000000000000", wantErr: true},
+		{name: "too many integer digits negative", input: "-1000000000000.00", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+		{name: "sign only", input: "-", wantErr: true},
+		{name: "letters", input: "abc", wantErr: true},
+		{name: "two dots", input: "1.2.3", wantErr: true},
+		{name: "exponent", input: "1e3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMoney) {
+					t.Fatalf("ParseMoney(%q) error = %v, want ErrInvalidMoney", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney(%q) unexpected error: %v", tt.input, err)
+			}
+			if got.Amount != tt.want {
+				t.Errorf("ParseMoney(%q) = %d, want %d", tt.input, got.Amount, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyMulRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		num, den int64
+		want     int64
+	}{
+		{name: "exact", amount: 300, num: 1, den: 3, want: 100},
+		{name: "round down", amount: 100, num: 1, den: 3, want: 33},
+		{name: "round up", amount: 200, num: 1, den: 3, want: 67},
+		{name: "half rounds up", amount: 5, num: 1, den: 2, want: 3},
+		{name: "negative half rounds away from zero", amount: -5, num: 1, den: 2, want: -3},
+		{name: "negative round", amount: -200, num: 1, den: 3, want: -67},
+		{name: "negative denominator", amount: 5, num: 1, den: -2, want: -3},
+		{name: "zero denominator", amount: 500, num: 1, den: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMoney(tt.amount).MulRatio(tt.num, tt.den)
+			if got.Amount != tt.want {
+				t.Errorf("MulRatio(%d, %d/%d) = %d, want %d", tt.amount, tt.num, tt.den, got.Amount, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      int64
+		basisPoints int64
+		want        int64
+	}{
+		{name: "eleven percent", amount: 1000000, basisPoints: 1100, want: 110000},
+		{name: "zero rate", amount: 1000000, basisPoints: 0, want: 0},
+		{name: "hundred percent", amount: 1234, basisPoints: 10000, want: 1234},
+		{name: "half minor unit rounds up", amount: 45, basisPoints: 1000, want: 5},
+		{name: "below half rounds down", amount: 44, basisPoints: 1000, want: 4},
+		{name: "negative half rounds away from zero", amount: -45, basisPoints: 1000, want: -5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMoney(tt.amount).Percent(tt.basisPoints)
+			if got.Amount != tt.want {
+				t.Errorf("Percent(%d, %d) = %d, want %d", tt.amount, tt.basisPoints, got.Amount, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		want         int64
+		wantCurrency string
+		wantErr      error
+	}{
+		{name: "number", input: `12500.5`, want: 1250050, wantCurrency: "IDR"},
+		{name: "string", input: `"12500.5"`, want: 1250050, wantCurrency: "IDR"},
+		{name: "object with currency", input: `{"amount": 12500.5, "currency": "IDR"}`, want: 1250050, wantCurrency: "IDR"},
+		{name: "object currency is case-insensitive", input: `{"amount": "1", "currency": " idr "}`, want: 100, wantCurrency: "IDR"},
+		{name: "object defaults to IDR", input: `{"amount": 2}`, want: 200, wantCurrency: "IDR"},
+		{name: "unknown currency", input: `{"amount": 2, "currency": "USD"}`, wantErr: ErrUnsupportedCurrency},
+		{name: "object without amount", input: `{"currency": "IDR"}`, wantErr: ErrInvalidMoney},
+		{name: "nested object amount", input: `{"amount": {"amount": 2}}`, wantErr: ErrInvalidMoney},
+		{name: "too many integer digits", input: `"1000000000000"`, wantErr: ErrInvalidMoney},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := got.UnmarshalJSON([]byte(tt.input))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("UnmarshalJSON(%s) error = %v, want %v", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnmarshalJSON(%s) unexpected error: %v", tt.input, err)
+			}
+			if got.Amount != tt.want || got.CurrencyCode() != tt.wantCurrency {
+				t.Errorf("UnmarshalJSON(%s) = %d %s, want %d %s", tt.input, got.Amount, got.CurrencyCode(), tt.want, tt.wantCurrency)
+			}
+		})
+	}
+}
+
+func TestMoneyCurrency(t *testing.T) {
+	idr := NewMoney(100)
+	usd := Money{Amount: 100, Currency: "USD"}
+
+	tests := []struct {
+		name      string
+		op        func() Money
+		want      int64
+		wantPanic bool
+	}{
+		{name: "zero value is default currency", op: func() Money { return Money{}.Add(idr) }, want: 100},
+		{name: "same currency", op: func() Money { return idr.Sub(NewMoney(30)) }, want: 70},
+		{name: "mul keeps currency", op: func() Money { return usd.Mul(3) }, want: 300},
+		{name: "add mixed currencies", op: func() Money { return idr.Add(usd) }, wantPanic: true},
+		{name: "sub mixed currencies", op: func() Money { return usd.Sub(idr) }, wantPanic: true},
+		{name: "compare mixed currencies", op: func() Money { idr.Cmp(usd); return idr }, wantPanic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if tt.wantPanic {
+					err, ok := r.(error)
+					if !ok || !errors.Is(err, ErrCurrencyMismatch) {
+						t.Errorf("expected ErrCurrencyMismatch panic, got %v", r)
+					}
+				} else if r != nil {
+					t.Errorf("unexpected panic: %v", r)
+				}
+			}()
+			if got := tt.op(); got.Amount != tt.want {
+				t.Errorf("got %d, want %d", got.Amount, tt.want)
+			}
+		})
+	}
+
+	if _, err := usd.Value(); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Value() of USD error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+	if v, err := idr.Value(); err != nil || v != "1.00" {
+		t.Errorf("Value() of IDR = %v, %v, want 1.00", v, err)
+	}
+}
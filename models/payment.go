@@ -0,0 +1,133 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	PaymentMethodCash   = "cash"
+	PaymentMethodQRIS   = "qris"
+	PaymentMethodDebit  = "debit"
+	PaymentMethodCredit = "credit"
+)
+
+var (
+	// ErrInvalidPayment dikembalikan jika metode atau nominal pembayaran tidak valid
+	ErrInvalidPayment = errors.New("pembayaran tidak valid")
+	// ErrInsufficientPayment dikembalikan jika jumlah pembayaran kurang dari total transaksi
+	ErrInsufficientPayment = errors.New("pembayaran kurang dari total transaksi")
+	// ErrNonCashOverpayment dikembalikan jika pembayaran non-tunai melebihi total transaksi (kembalian hanya dari tunai)
+	ErrNonCashOverpayment = errors.New("pembayaran non-tunai melebihi total transaksi")
+)
+
+// Payment adalah satu pembayaran (tender) pada transaksi. Amount adalah nominal yang diserahkan
+// pelanggan; ChangeAmount adalah kembalian yang diambil dari pembayaran ini (hanya tunai).
+type Payment struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	Method        string `json:"method" enums:"cash,qris,debit,credit"`
+	Amount        Money  `json:"amount" swaggertype:"number"`
+	ChangeAmount  Money  `json:"change_amount" swaggertype:"number"`
+	Reference     string `json:"reference,omitempty"` // mis. nomor approval EDC / id QRIS
+}
+
+// CheckoutPayment adalah pembayaran yang dikirim saat checkout
+type CheckoutPayment struct {
+	Method    string `json:"method" enums:"cash,qris,debit,credit"`
+	Amount    Money  `json:"amount" swaggertype:"number"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// PaymentMethodTotal adalah ringkasan pembayaran per metode pada suatu periode
+type PaymentMethodTotal struct {
+	Method       string `json:"method"`
+	Count        int    `json:"count"`
+	Amount       Money  `json:"amount" swaggertype:"number"` // nominal diterima
+	ChangeAmount Money  `json:"change_amount" swaggertype:"number"`
+	RefundAmount Money  `json:"refund_amount" swaggertype:"number"` // uang yang dikembalikan lewat metode ini oleh refund/void
+	NetAmount    Money  `json:"net_amount" swaggertype:"number"`    // amount - change - refund, yang seharusnya ada di laci/rekening
+}
+
+// IsValidPaymentMethod memeriksa apakah metode pembayaran dikenal
+func IsValidPaymentMethod(method string) bool {
+	switch method {
+	case PaymentMethodCash, PaymentMethodQRIS, PaymentMethodDebit, PaymentMethodCredit:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate memeriksa metode dan nominal setiap pembayaran
+func (p CheckoutPayment) Validate() error {
+	if !IsValidPaymentMethod(p.Method) {
+		return fmt.Errorf("%w: method harus salah satu dari cash, qris, debit, credit", ErrInvalidPayment)
+	}
+	if p.Amount.Amount <= 0 {
+		return fmt.Errorf("%w: amount untuk %s harus lebih dari 0", ErrInvalidPayment, p.Method)
+	}
+	if len(p.Reference) > 100 {
+		return fmt.Errorf("%w: reference maksimal 100 karakter", ErrInvalidPayment)
+	}
+	return nil
+}
+
+// SettlePayments mencocokkan pembayaran dengan total transaksi dan menghitung kembalian.
+// Jumlah pembayaran harus >= total, dan pembayaran non-tunai tidak boleh melebihi total karena
+// kembalian hanya dapat diberikan dari tunai. Kembalian dibebankan ke pembayaran tunai sesuai urutan.
+// Tanpa pembayaran, transaksi dianggap dibayar tunai pas (atau tanpa pembayaran jika total 0).
+func SettlePayments(total Money, payments []CheckoutPayment) ([]Payment, Money, error) {
+	if len(payments) == 0 {
+		if total.IsZero() {
+			return nil, NewMoney(0), nil
+		}
+		return []Payment{{Method: PaymentMethodCash, Amount: total, ChangeAmount: NewMoney(0)}}, NewMoney(0), nil
+	}
+
+	paid := NewMoney(0)
+	nonCash := NewMoney(0)
+	result := make([]Payment, 0, len(payments))
+	for _, p := range payments {
+		if err := p.Validate(); err != nil {
+			return nil, Money{}, err
+		}
+		paid = paid.Add(p.Amount)
+		if p.Method != PaymentMethodCash {
+			nonCash = nonCash.Add(p.Amount)
+		}
+		result = append(result, Payment{
+			Method:       p.Method,
+			Amount:       p.Amount,
+			ChangeAmount: NewMoney(0),
+			Reference:    strings.TrimSpace(p.Reference),
+		})
+	}
+
+	if paid.Cmp(total) < 0 {
+		return nil, Money{}, fmt.Errorf("%w: total %s, dibayar %s", ErrInsufficientPayment, total, paid)
+	}
+	if nonCash.Cmp(total) > 0 {
+		return nil, Money{}, fmt.Errorf("%w: total %s, non-tunai %s", ErrNonCashOverpayment, total, nonCash)
+	}
+
+	change := paid.Sub(total)
+	remaining := change
+	for i := range result {
+		if remaining.Amount <= 0 {
+			break
+		}
+		if result[i].Method != PaymentMethodCash {
+			continue
+		}
+		share := result[i].Amount
+		if share.Cmp(remaining) > 0 {
+			share = remaining
+		}
+		result[i].ChangeAmount = share
+		remaining = remaining.Sub(share)
+	}
+
+	return result, change, nil
+}
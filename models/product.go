@@ -1,12 +1,31 @@
 package models
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type Product struct {
-	ID           int     `json:"id"`
-	CategoryID   int     `json:"category_id"`
-	CategoryName string  `json:"category_name"`
-	Name         string  `json:"name"`
-	Price        float64 `json:"price"`
-	Stock        int     `json:"stock"`
+	ID           int        `json:"id"`
+	CategoryID   int        `json:"category_id"`
+	CategoryName string     `json:"category_name"`
+	Name         string     `json:"name"`
+	Price        Money      `json:"price" swaggertype:"number" example:"12500.50"`
+	Stock        int        `json:"stock"`
+	CostPrice    Money      `json:"cost_price" swaggertype:"number" example:"9000"` // harga pokok rata-rata tertimbang, diperbarui saat penerimaan barang
+	MinStock     int        `json:"min_stock"`                                      // batas stok minimum; 0 = tanpa peringatan stok menipis
+	ReorderQty   int        `json:"reorder_qty"`                                    // quantity pesan ulang minimum saat stok menipis
+	TaxRateID    *int       `json:"tax_rate_id"`                                    // jika kosong, memakai tarif pajak kategori
+	SKU          string     `json:"sku" example:"MNM-001"`                          // unik; kosong berarti produk belum punya SKU
+	Barcodes     []string   `json:"barcodes" example:"8992761111113"`               // EAN-8, UPC-A atau EAN-13; unik antar produk
+	HasVariants  bool       `json:"has_variants"`                                   // true jika stok dan harga diatur per varian
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`                           // terisi jika produk sudah dihapus (soft delete)
+	Version      int        `json:"version"`                                        // naik setiap perubahan; dikirim sebagai ETag
 }
 
 // Model untuk menampilkan produk terlaris dengan jumlah terjual
@@ -14,3 +33,122 @@ type TopSellingProduct struct {
 	Name    string `json:"name"`
 	QtySold int    `json:"qty_sold"`
 }
+
+const (
+	ProductSortID    = "id"
+	ProductSortName  = "name"
+	ProductSortPrice = "price"
+	ProductSortStock = "stock"
+)
+
+var (
+	// ErrInvalidCursor dikembalikan jika cursor pagination produk tidak bisa dibaca atau tidak cocok dengan urutan yang diminta
+	ErrInvalidCursor = errors.New("cursor tidak valid")
+	// ErrProductNotDeleted dikembalikan jika restore dipanggil untuk produk yang tidak sedang dihapus
+	ErrProductNotDeleted = errors.New("produk tidak dalam keadaan terhapus")
+	// ErrInvalidProduct dikembalikan jika data produk hasil PATCH tidak valid
+	ErrInvalidProduct = errors.New("produk tidak valid")
+)
+
+// Validate memeriksa field wajib dan batas nilai produk, lalu merapikan SKU dan barcode lewat NormalizeCodes
+func (p *Product) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	switch {
+	case p.Name == "":
+		return fmt.Errorf("%w: name wajib diisi", ErrInvalidProduct)
+	case len(p.Name) > 255:
+		return fmt.Errorf("%w: name maksimal 255 karakter", ErrInvalidProduct)
+	case p.CategoryID <= 0:
+		return fmt.Errorf("%w: category_id wajib diisi", ErrInvalidProduct)
+	case p.Price.IsNegative(), p.CostPrice.IsNegative():
+		return fmt.Errorf("%w: price dan cost_price tidak boleh negatif", ErrInvalidProduct)
+	case p.Stock < 0, p.MinStock < 0, p.ReorderQty < 0:
+		return fmt.Errorf("%w: stock, min_stock dan reorder_qty tidak boleh negatif", ErrInvalidProduct)
+	}
+	return p.NormalizeCodes()
+}
+
+// ProductFilter berisi filter, urutan dan pagination daftar produk.
+// Field bernilai kosong/nil berarti filter tersebut tidak dipakai.
+// Jika Cursor diisi, pagination memakai keyset (Page diabaikan); selain itu memakai offset.
+type ProductFilter struct {
+	Name       string // ILIKE
+	CategoryID int    // termasuk seluruh sub-kategori
+	MinPrice   *Money
+	MaxPrice   *Money
+	InStock    *bool // true: stock > 0, false: stock <= 0
+	StockMin   *int
+	StockMax   *int
+	Sort       string // id, name, price, stock
+	Desc       bool
+	Page       int
+	Limit      int
+	Cursor     *ProductCursor
+
+	IncludeDeleted bool // tampilkan juga produk yang sudah dihapus (soft delete)
+}
+
+// ProductCursor menandai posisi terakhir (atau pertama jika Backward) pada urutan tertentu untuk keyset pagination
+type ProductCursor struct {
+	Sort     string `json:"s"`
+	Desc     bool   `json:"d,omitempty"`
+	Value    string `json:"v"` // nilai kolom sort pada baris acuan
+	ID       int    `json:"id"`
+	Backward bool   `json:"b,omitempty"` // true: ambil halaman sebelum baris acuan
+}
+
+// Encode mengubah cursor menjadi string opaque yang aman dipakai di query string
+func (c ProductCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeProductCursor membaca cursor hasil Encode. Value harus sesuai tipe kolom sort-nya
+// agar cursor yang diubah klien ditolak di sini, bukan gagal saat di-cast oleh database.
+func DecodeProductCursor(s string) (*ProductCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c ProductCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	switch c.Sort {
+	case ProductSortID, ProductSortStock:
+		if _, err := strconv.ParseInt(c.Value, 10, 32); err != nil {
+			return nil, fmt.Errorf("%w: nilai %s tidak valid", ErrInvalidCursor, c.Sort)
+		}
+	case ProductSortPrice:
+		if _, err := ParseMoney(c.Value); err != nil {
+			return nil, fmt.Errorf("%w: nilai price tidak valid", ErrInvalidCursor)
+		}
+	case ProductSortName:
+	default:
+		return nil, fmt.Errorf("%w: sort tidak dikenal", ErrInvalidCursor)
+	}
+	return &c, nil
+}
+
+// SortValue mengembalikan nilai kolom sort produk dalam bentuk string untuk cursor
+func (p Product) SortValue(sort string) string {
+	switch sort {
+	case ProductSortName:
+		return p.Name
+	case ProductSortPrice:
+		return p.Price.String()
+	case ProductSortStock:
+		return strconv.Itoa(p.Stock)
+	default:
+		return strconv.Itoa(p.ID)
+	}
+}
+
+// ProductPage adalah satu halaman daftar produk; cursor kosong berarti tidak ada halaman berikutnya/sebelumnya
+type ProductPage struct {
+	Products   []Product
+	Total      int // jumlah seluruh produk yang cocok dengan filter
+	NextCursor string
+	PrevCursor string
+}
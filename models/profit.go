@@ -0,0 +1,46 @@
+package models
+
+// WeightedAverageCost menghitung harga pokok rata-rata tertimbang setelah menerima qty barang seharga unitCost
+// di atas stok lama. Jika stok lama tidak positif, harga pokok baru adalah unitCost.
+func WeightedAverageCost(cost Money, stock int, unitCost Money, qty int) Money {
+	if stock <= 0 {
+		return unitCost
+	}
+	if qty <= 0 {
+		return cost
+	}
+	total := cost.Mul(int64(stock)).Add(unitCost.Mul(int64(qty)))
+	return total.MulRatio(1, int64(stock+qty))
+}
+
+// MarginPercent mengembalikan profit / sales x 100 dengan 2 desimal (half away from zero), 0 jika sales 0
+func MarginPercent(profit, sales Money) float64 {
+	if sales.IsZero() {
+		return 0
+	}
+	return float64(divRoundHalfAway(profit.Amount*10000, sales.Amount)) / 100
+}
+
+// ProfitLine adalah penjualan bersih, HPP dan laba kotor satu produk atau satu kategori pada suatu periode.
+// Refund/void di dalam periode mengurangi quantity, penjualan dan HPP memakai harga pokok saat checkout.
+type ProfitLine struct {
+	ID            *int    `json:"id"` // null jika produk/kategori sudah dihapus
+	Name          string  `json:"name"`
+	QtySold       int     `json:"qty_sold"`
+	NetSales      Money   `json:"net_sales" swaggertype:"number"` // setelah diskon, tanpa pajak
+	COGS          Money   `json:"cogs" swaggertype:"number"`
+	GrossProfit   Money   `json:"gross_profit" swaggertype:"number"`
+	MarginPercent float64 `json:"margin_percent"`
+	ProductID     *int    `json:"product_id,omitempty"` // hanya diisi pada rincian varian
+}
+
+// ProfitReport adalah ringkasan laba kotor suatu periode beserta rinciannya per produk dan per kategori
+type ProfitReport struct {
+	NetSales      Money        `json:"net_sales" swaggertype:"number"`
+	COGS          Money        `json:"cogs" swaggertype:"number"`
+	GrossProfit   Money        `json:"gross_profit" swaggertype:"number"`
+	MarginPercent float64      `json:"margin_percent"`
+	Products      []ProfitLine `json:"products"`
+	Categories    []ProfitLine `json:"categories"`
+	Variants      []ProfitLine `json:"variants"` // rincian per varian untuk produk bervarian; baris produk sudah berisi totalnya
+}
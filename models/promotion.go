@@ -0,0 +1,345 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// PromotionTypePercent memberi diskon persen untuk produk/kategori tertentu (atau semua produk)
+	PromotionTypePercent = "percent"
+	// PromotionTypeBuyXGetY memberi gratis GetQty unit untuk setiap BuyQty+GetQty unit produk/kategori
+	PromotionTypeBuyXGetY = "buy_x_get_y"
+	// PromotionTypeCartFixed memberi potongan nominal tetap untuk seluruh keranjang (voucher)
+	PromotionTypeCartFixed = "cart_fixed"
+)
+
+var (
+	// ErrPromotionNotFound dikembalikan jika promo dengan id tertentu tidak ada
+	ErrPromotionNotFound = errors.New("promo tidak ditemukan")
+	// ErrInvalidPromotion dikembalikan jika payload promo tidak valid
+	ErrInvalidPromotion = errors.New("promo tidak valid")
+	// ErrVoucherNotApplicable dikembalikan jika voucher pada checkout tidak ada, tidak aktif, syaratnya tidak terpenuhi,
+	// atau kalah dari promo lain yang tidak bisa digabung
+	ErrVoucherNotApplicable = errors.New("voucher tidak dapat digunakan")
+)
+
+// Promotion adalah aturan diskon yang dievaluasi saat checkout.
+//
+// Aturan stacking: untuk setiap baris (dan untuk keranjang), semua promo yang Stackable
+// dijumlahkan sebagai satu opsi, sedangkan setiap promo non-stackable menjadi opsi tersendiri.
+// Opsi dengan diskon terbesar yang dipakai; jika sama besar, opsi stackable yang dipilih.
+type Promotion struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Type        string     `json:"type" enums:"percent,buy_x_get_y,cart_fixed"`
+	Code        string     `json:"code"`        // kode voucher; kosong berarti promo otomatis
+	ProductID   *int       `json:"product_id"`  // target produk (percent, buy_x_get_y)
+	CategoryID  *int       `json:"category_id"` // target kategori (percent, buy_x_get_y)
+	PercentBP   int        `json:"percent_bp"`  // basis point untuk tipe percent, 1000 = 10%
+	BuyQty      int        `json:"buy_qty"`
+	GetQty      int        `json:"get_qty"`
+	Amount      Money      `json:"amount" swaggertype:"number"`       // potongan untuk cart_fixed
+	MinSubtotal Money      `json:"min_subtotal" swaggertype:"number"` // minimal belanja untuk cart_fixed
+	Stackable   bool       `json:"stackable"`
+	Priority    int        `json:"priority"`
+	Active      bool       `json:"active"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+
+	// CategoryIDs adalah category_id beserta seluruh sub-kategorinya, diisi repository saat promo dievaluasi.
+	// Kosong berarti hanya category_id yang cocok.
+	CategoryIDs []int `json:"-"`
+}
+
+// LineDiscount adalah rincian diskon satu promo pada satu baris transaksi
+type LineDiscount struct {
+	PromotionID   int    `json:"promotion_id"`
+	PromotionName string `json:"promotion_name"`
+	Amount        Money  `json:"amount" swaggertype:"number"`
+}
+
+// Validate memeriksa field wajib sesuai tipe promo
+func (p Promotion) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmtPromotionErr("name wajib diisi")
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return fmtPromotionErr("ends_at harus setelah starts_at")
+	}
+
+	switch p.Type {
+	case PromotionTypePercent:
+		if p.PercentBP <= 0 || p.PercentBP > 10000 {
+			return fmtPromotionErr("percent_bp harus antara 1 dan 10000")
+		}
+	case PromotionTypeBuyXGetY:
+		if p.BuyQty <= 0 || p.GetQty <= 0 {
+			return fmtPromotionErr("buy_qty dan get_qty harus lebih dari 0")
+		}
+		if p.ProductID == nil && p.CategoryID == nil {
+			return fmtPromotionErr("buy_x_get_y membutuhkan product_id atau category_id")
+		}
+	case PromotionTypeCartFixed:
+		if p.Amount.Amount <= 0 {
+			return fmtPromotionErr("amount harus lebih dari 0")
+		}
+		if p.MinSubtotal.IsNegative() {
+			return fmtPromotionErr("min_subtotal tidak boleh negatif")
+		}
+	default:
+		return fmtPromotionErr("type harus salah satu dari percent, buy_x_get_y, cart_fixed")
+	}
+
+	return nil
+}
+
+func fmtPromotionErr(msg string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidPromotion, msg)
+}
+
+// IsCartLevel bernilai true untuk promo yang berlaku pada total keranjang
+func (p Promotion) IsCartLevel() bool {
+	return p.Type == PromotionTypeCartFixed
+}
+
+// matchesLine memeriksa apakah promo level baris berlaku untuk produk/kategori tertentu.
+// Promo kategori juga berlaku untuk produk di sub-kategorinya.
+func (p Promotion) matchesLine(productID, categoryID int) bool {
+	if p.ProductID != nil && *p.ProductID != productID {
+		return false
+	}
+	if p.CategoryID == nil || *p.CategoryID == categoryID {
+		return true
+	}
+	return slices.Contains(p.CategoryIDs, categoryID)
+}
+
+// lineDiscount menghitung diskon promo level baris untuk harga satuan dan quantity tertentu
+func (p Promotion) lineDiscount(unitPrice Money, quantity int) Money {
+	switch p.Type {
+	case PromotionTypePercent:
+		return unitPrice.Mul(int64(quantity)).Percent(int64(p.PercentBP))
+	case PromotionTypeBuyXGetY:
+		free := quantity / (p.BuyQty + p.GetQty) * p.GetQty
+		return unitPrice.Mul(int64(free))
+	default:
+		return NewMoney(0)
+	}
+}
+
+// PromotionLine adalah input satu baris keranjang untuk ApplyPromotions
+type PromotionLine struct {
+	ProductID  int
+	CategoryID int
+	UnitPrice  Money
+	Quantity   int
+}
+
+// PromotionResult adalah hasil evaluasi promo untuk satu baris
+type PromotionResult struct {
+	DiscountAmount Money
+	Discounts      []LineDiscount
+}
+
+type discountOption struct {
+	total     Money
+	discounts []LineDiscount
+}
+
+// ApplyPromotions mengevaluasi promo aktif terhadap baris keranjang dan mengembalikan rincian diskon
+// per baris (urutan sama dengan lines). Promo level baris dievaluasi lebih dulu, lalu promo keranjang
+// dihitung dari subtotal setelah diskon baris dan dialokasikan proporsional ke setiap baris.
+// Voucher (promo dengan code) hanya dipakai jika code-nya ada di voucherCodes; setiap voucher
+// di voucherCodes harus benar-benar terpakai (masuk opsi terbaik minimal di satu baris atau di keranjang),
+// jika tidak ErrVoucherNotApplicable dikembalikan, termasuk saat voucher kalah dari promo yang lebih besar.
+func ApplyPromotions(lines []PromotionLine, promotions []Promotion, voucherCodes []string) ([]PromotionResult, error) {
+	promotions = append([]Promotion(nil), promotions...)
+	sort.SliceStable(promotions, func(i, j int) bool {
+		if promotions[i].Priority != promotions[j].Priority {
+			return promotions[i].Priority < promotions[j].Priority
+		}
+		return promotions[i].ID < promotions[j].ID
+	})
+
+	requested := make(map[string]bool, len(voucherCodes))
+	for _, code := range voucherCodes {
+		requested[strings.ToUpper(strings.TrimSpace(code))] = true
+	}
+	used := make(map[string]bool, len(voucherCodes))
+	codes := make(map[int]string)
+	for _, p := range promotions {
+		if p.Code != "" {
+			codes[p.ID] = strings.ToUpper(p.Code)
+		}
+	}
+	markUsed := func(discounts []LineDiscount) {
+		for _, d := range discounts {
+			if code, ok := codes[d.PromotionID]; ok {
+				used[code] = true
+			}
+		}
+	}
+
+	eligible := func(p Promotion) bool {
+		if p.Code == "" {
+			return true
+		}
+		return requested[strings.ToUpper(p.Code)]
+	}
+
+	results := make([]PromotionResult, len(lines))
+	nets := make([]Money, len(lines))
+
+	// Diskon level baris
+	for i, line := range lines {
+		gross := line.UnitPrice.Mul(int64(line.Quantity))
+		options := []discountOption{{total: NewMoney(0)}}
+
+		for _, p := range promotions {
+			if p.IsCartLevel() || !eligible(p) || !p.matchesLine(line.ProductID, line.CategoryID) {
+				continue
+			}
+			amount := p.lineDiscount(line.UnitPrice, line.Quantity)
+			if amount.Amount <= 0 {
+				continue
+			}
+			d := LineDiscount{PromotionID: p.ID, PromotionName: p.Name, Amount: amount}
+			if p.Stackable {
+				options[0].total = options[0].total.Add(amount)
+				options[0].discounts = append(options[0].discounts, d)
+			} else {
+				options = append(options, discountOption{total: amount, discounts: []LineDiscount{d}})
+			}
+		}
+
+		best := bestOption(options, gross)
+		markUsed(best.discounts)
+		results[i] = PromotionResult{DiscountAmount: best.total, Discounts: best.discounts}
+		nets[i] = gross.Sub(best.total)
+	}
+
+	// Diskon level keranjang
+	cartNet := NewMoney(0)
+	for _, net := range nets {
+		cartNet = cartNet.Add(net)
+	}
+
+	options := []discountOption{{total: NewMoney(0)}}
+	for _, p := range promotions {
+		if !p.IsCartLevel() || !eligible(p) {
+			continue
+		}
+		if cartNet.Cmp(p.MinSubtotal) < 0 {
+			continue
+		}
+		d := LineDiscount{PromotionID: p.ID, PromotionName: p.Name, Amount: p.Amount}
+		if p.Stackable {
+			options[0].total = options[0].total.Add(p.Amount)
+			options[0].discounts = append(options[0].discounts, d)
+		} else {
+			options = append(options, discountOption{total: p.Amount, discounts: []LineDiscount{d}})
+		}
+	}
+
+	best := bestOption(options, cartNet)
+	markUsed(best.discounts)
+
+	for code := range requested {
+		if code != "" && !used[code] {
+			return nil, fmt.Errorf("%w: %s", ErrVoucherNotApplicable, code)
+		}
+	}
+
+	for _, d := range best.discounts {
+		shares := allocateProportionally(d.Amount, nets)
+		for i, share := range shares {
+			if share.IsZero() {
+				continue
+			}
+			results[i].DiscountAmount = results[i].DiscountAmount.Add(share)
+			results[i].Discounts = append(results[i].Discounts, LineDiscount{
+				PromotionID:   d.PromotionID,
+				PromotionName: d.PromotionName,
+				Amount:        share,
+			})
+			nets[i] = nets[i].Sub(share)
+		}
+	}
+
+	return results, nil
+}
+
+// bestOption memilih opsi dengan diskon terbesar lalu membatasi totalnya agar tidak melebihi limit.
+// Jika dibatasi, diskon terakhir dikurangi sehingga jumlah rincian tetap sama dengan total.
+func bestOption(options []discountOption, limit Money) discountOption {
+	best := options[0]
+	for _, o := range options[1:] {
+		if o.total.Cmp(best.total) > 0 {
+			best = o
+		}
+	}
+
+	if best.total.Cmp(limit) <= 0 {
+		return best
+	}
+
+	capped := discountOption{total: NewMoney(0)}
+	remaining := limit
+	for _, d := range best.discounts {
+		if remaining.Amount <= 0 {
+			break
+		}
+		if d.Amount.Cmp(remaining) > 0 {
+			d.Amount = remaining
+		}
+		remaining = remaining.Sub(d.Amount)
+		capped.total = capped.total.Add(d.Amount)
+		capped.discounts = append(capped.discounts, d)
+	}
+	return capped
+}
+
+// allocateProportionally membagi amount ke setiap baris sebanding dengan nilainya (dibulatkan ke bawah),
+// lalu sisa sen dibagikan satu per satu ke baris yang masih punya ruang. Tidak ada baris yang
+// menerima lebih dari nilainya, dan jumlah hasil selalu sama dengan amount (jika amount <= total).
+func allocateProportionally(amount Money, weights []Money) []Money {
+	shares := make([]Money, len(weights))
+	total := int64(0)
+	for _, w := range weights {
+		total += w.Amount
+	}
+	if total <= 0 {
+		return shares
+	}
+
+	allocated := int64(0)
+	for i, w := range weights {
+		share := amount.Amount * w.Amount / total
+		shares[i] = NewMoney(share)
+		allocated += share
+	}
+
+	for allocated < amount.Amount {
+		progressed := false
+		for i := range weights {
+			if allocated >= amount.Amount {
+				break
+			}
+			if shares[i].Amount < weights[i].Amount {
+				shares[i] = shares[i].Add(NewMoney(1))
+				allocated++
+				progressed = true
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	return shares
+}
@@ -0,0 +1,347 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApplyPromotions(t *testing.T) {
+	productOne := 1
+	categoryTen := 10
+
+	// Baris default: produk 1 (kategori 10) 2 x 10000 dan produk 2 (kategori 20) 1 x 5000
+	twoLines := []PromotionLine{
+		{ProductID: 1, CategoryID: 10, UnitPrice: NewMoney(1000000), Quantity: 2},
+		{ProductID: 2, CategoryID: 20, UnitPrice: NewMoney(500000), Quantity: 1},
+	}
+
+	tests := []struct {
+		name       string
+		lines      []PromotionLine
+		promotions []Promotion
+		vouchers   []string
+		want       []string
+		wantIDs    [][]int
+		wantErr    error
+	}{
+		{
+			name:    "no promotions",
+			lines:   twoLines,
+			want:    []string{"0.00", "0.00"},
+			wantIDs: [][]int{nil, nil},
+		},
+		{
+			name:  "percent on product",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, ProductID: &productOne, PercentBP: 1000},
+			},
+			want:    []string{"2000.00", "0.00"},
+			wantIDs: [][]int{{1}, nil},
+		},
+		{
+			name:  "stackable promotions are summed",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, ProductID: &productOne, PercentBP: 1000, Stackable: true},
+				{ID: 2, Type: PromotionTypePercent, CategoryID: &categoryTen, PercentBP: 500, Stackable: true},
+			},
+			want:    []string{"3000.00", "0.00"},
+			wantIDs: [][]int{{1, 2}, nil},
+		},
+		{
+			name:  "category promotion applies to subcategories",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, CategoryID: &categoryTen, CategoryIDs: []int{10, 20}, PercentBP: 1000},
+			},
+			want:    []string{"2000.00", "500.00"},
+			wantIDs: [][]int{{1}, {1}},
+		},
+		{
+			name:  "category promotion skips other categories",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, CategoryID: &categoryTen, CategoryIDs: []int{10, 30}, PercentBP: 1000},
+			},
+			want:    []string{"2000.00", "0.00"},
+			wantIDs: [][]int{{1}, nil},
+		},
+		{
+			name:  "larger non-stackable beats stackable group",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, ProductID: &productOne, PercentBP: 1000, Stackable: true},
+				{ID: 2, Type: PromotionTypePercent, CategoryID: &categoryTen, PercentBP: 2000},
+			},
+			want:    []string{"4000.00", "0.00"},
+			wantIDs: [][]int{{2}, nil},
+		},
+		{
+			name:  "tie prefers stackable group",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, ProductID: &productOne, PercentBP: 1000, Stackable: true},
+				{ID: 2, Type: PromotionTypePercent, ProductID: &productOne, PercentBP: 1000},
+			},
+			want:    []string{"2000.00", "0.00"},
+			wantIDs: [][]int{{1}, nil},
+		},
+		{
+			name:  "buy x get y",
+			lines: []PromotionLine{{ProductID: 1, CategoryID: 10, UnitPrice: NewMoney(100000), Quantity: 7}},
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypeBuyXGetY, ProductID: &productOne, BuyQty: 2, GetQty: 1},
+			},
+			want:    []string{"2000.00"},
+			wantIDs: [][]int{{1}},
+		},
+		{
+			name:  "line discount capped at line total",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, ProductID: &productOne, PercentBP: 10000, Stackable: true},
+				{ID: 2, Type: PromotionTypePercent, ProductID: &productOne, PercentBP: 1000, Stackable: true},
+			},
+			want:    []string{"20000.00", "0.00"},
+			wantIDs: [][]int{{1}, nil},
+		},
+		{
+			name:  "cart fixed allocated proportionally",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 3, Type: PromotionTypeCartFixed, Amount: NewMoney(100000), MinSubtotal: NewMoney(2000000)},
+			},
+			want:    []string{"800.00", "200.00"},
+			wantIDs: [][]int{{3}, {3}},
+		},
+		{
+			name:  "cart fixed below min subtotal",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 3, Type: PromotionTypeCartFixed, Amount: NewMoney(100000), MinSubtotal: NewMoney(3000000)},
+			},
+			want:    []string{"0.00", "0.00"},
+			wantIDs: [][]int{nil, nil},
+		},
+		{
+			name:  "cart min subtotal uses total after line discounts",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, ProductID: &productOne, PercentBP: 1000},
+				{ID: 3, Type: PromotionTypeCartFixed, Amount: NewMoney(100000), MinSubtotal: NewMoney(2500000)},
+			},
+			want:    []string{"2000.00", "0.00"},
+			wantIDs: [][]int{{1}, nil},
+		},
+		{
+			name:  "voucher ignored when not requested",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, Code: "HEMAT", ProductID: &productOne, PercentBP: 1000},
+			},
+			want:    []string{"0.00", "0.00"},
+			wantIDs: [][]int{nil, nil},
+		},
+		{
+			name:  "requested voucher matched case-insensitively",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, Code: "HEMAT", ProductID: &productOne, PercentBP: 1000},
+			},
+			vouchers: []string{" hemat "},
+			want:     []string{"2000.00", "0.00"},
+			wantIDs:  [][]int{{1}, nil},
+		},
+		{
+			name:  "stackable voucher combined with automatic promotion",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, ProductID: &productOne, PercentBP: 1000, Stackable: true},
+				{ID: 2, Type: PromotionTypePercent, Code: "HEMAT", ProductID: &productOne, PercentBP: 500, Stackable: true},
+			},
+			vouchers: []string{"HEMAT"},
+			want:     []string{"3000.00", "0.00"},
+			wantIDs:  [][]int{{1, 2}, nil},
+		},
+		{
+			name:     "unknown voucher",
+			lines:    twoLines,
+			vouchers: []string{"NOPE"},
+			wantErr:  ErrVoucherNotApplicable,
+		},
+		{
+			name:  "line voucher losing to larger promotion",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 1, Type: PromotionTypePercent, ProductID: &productOne, PercentBP: 2000},
+				{ID: 2, Type: PromotionTypePercent, Code: "HEMAT", ProductID: &productOne, PercentBP: 1000},
+			},
+			vouchers: []string{"HEMAT"},
+			wantErr:  ErrVoucherNotApplicable,
+		},
+		{
+			name:  "cart voucher losing to larger cart promotion",
+			lines: twoLines,
+			promotions: []Promotion{
+				{ID: 3, Type: PromotionTypeCartFixed, Amount: NewMoney(200000)},
+				{ID: 4, Type: PromotionTypeCartFixed, Code: "KECIL", Amount: NewMoney(100000)},
+			},
+			vouchers: []string{"KECIL"},
+			wantErr:  ErrVoucherNotApplicable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := ApplyPromotions(tt.lines, tt.promotions, tt.vouchers)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ApplyPromotions() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyPromotions() unexpected error: %v", err)
+			}
+
+			for i, r := range results {
+				if r.DiscountAmount.String() != tt.want[i] {
+					t.Errorf("line %d discount = %s, want %s", i, r.DiscountAmount, tt.want[i])
+				}
+
+				var ids []int
+				sum := NewMoney(0)
+				for _, d := range r.Discounts {
+					ids = append(ids, d.PromotionID)
+					sum = sum.Add(d.Amount)
+				}
+				if !reflect.DeepEqual(ids, tt.wantIDs[i]) {
+					t.Errorf("line %d promotions = %v, want %v", i, ids, tt.wantIDs[i])
+				}
+				if sum.Cmp(r.DiscountAmount) != 0 {
+					t.Errorf("line %d discount breakdown sums to %s, want %s", i, sum, r.DiscountAmount)
+				}
+			}
+		})
+	}
+}
+
+func TestBestOption(t *testing.T) {
+	discount := func(id int, amount int64) LineDiscount {
+		return LineDiscount{PromotionID: id, Amount: NewMoney(amount)}
+	}
+
+	tests := []struct {
+		name        string
+		options     []discountOption
+		limit       int64
+		wantTotal   int64
+		wantAmounts []int64
+	}{
+		{
+			name:      "only empty option",
+			options:   []discountOption{{total: NewMoney(0)}},
+			limit:     1000,
+			wantTotal: 0,
+		},
+		{
+			name: "largest option wins",
+			options: []discountOption{
+				{total: NewMoney(300), discounts: []LineDiscount{discount(1, 100), discount(2, 200)}},
+				{total: NewMoney(500), discounts: []LineDiscount{discount(3, 500)}},
+			},
+			limit:       1000,
+			wantTotal:   500,
+			wantAmounts: []int64{500},
+		},
+		{
+			name: "tie keeps first option",
+			options: []discountOption{
+				{total: NewMoney(500), discounts: []LineDiscount{discount(1, 200), discount(2, 300)}},
+				{total: NewMoney(500), discounts: []LineDiscount{discount(3, 500)}},
+			},
+			limit:       1000,
+			wantTotal:   500,
+			wantAmounts: []int64{200, 300},
+		},
+		{
+			name: "capped total trims last discount",
+			options: []discountOption{
+				{total: NewMoney(300), discounts: []LineDiscount{discount(1, 100), discount(2, 200)}},
+			},
+			limit:       250,
+			wantTotal:   250,
+			wantAmounts: []int64{100, 150},
+		},
+		{
+			name: "cap drops discounts beyond limit",
+			options: []discountOption{
+				{total: NewMoney(300), discounts: []LineDiscount{discount(1, 100), discount(2, 200)}},
+			},
+			limit:       100,
+			wantTotal:   100,
+			wantAmounts: []int64{100},
+		},
+		{
+			name: "zero limit",
+			options: []discountOption{
+				{total: NewMoney(300), discounts: []LineDiscount{discount(1, 300)}},
+			},
+			limit:     0,
+			wantTotal: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best := bestOption(tt.options, NewMoney(tt.limit))
+			if best.total.Amount != tt.wantTotal {
+				t.Errorf("bestOption() total = %d, want %d", best.total.Amount, tt.wantTotal)
+			}
+			var amounts []int64
+			for _, d := range best.discounts {
+				amounts = append(amounts, d.Amount.Amount)
+			}
+			if !reflect.DeepEqual(amounts, tt.wantAmounts) {
+				t.Errorf("bestOption() amounts = %v, want %v", amounts, tt.wantAmounts)
+			}
+		})
+	}
+}
+
+func TestAllocateProportionally(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		weights []int64
+		want    []int64
+	}{
+		{name: "even split", amount: 100, weights: []int64{100, 100}, want: []int64{50, 50}},
+		{name: "proportional split", amount: 1000, weights: []int64{2000000, 500000}, want: []int64{800, 200}},
+		{name: "remainder goes to first lines", amount: 100, weights: []int64{100, 100, 100}, want: []int64{34, 33, 33}},
+		{name: "share never exceeds weight", amount: 10, weights: []int64{1, 100}, want: []int64{1, 9}},
+		{name: "zero weight line gets nothing", amount: 100, weights: []int64{0, 300}, want: []int64{0, 100}},
+		{name: "amount equals total", amount: 300, weights: []int64{100, 200}, want: []int64{100, 200}},
+		{name: "zero total", amount: 100, weights: []int64{0, 0}, want: []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights := make([]Money, len(tt.weights))
+			for i, w := range tt.weights {
+				weights[i] = NewMoney(w)
+			}
+
+			shares := allocateProportionally(NewMoney(tt.amount), weights)
+			got := make([]int64, len(shares))
+			for i, s := range shares {
+				got[i] = s.Amount
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allocateProportionally(%d, %v) = %v, want %v", tt.amount, tt.weights, got, tt.want)
+			}
+		})
+	}
+}
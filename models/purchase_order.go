@@ -0,0 +1,163 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// POStatusDraft adalah PO yang masih bisa diubah dan belum dikirim ke supplier
+	POStatusDraft = "draft"
+	// POStatusSent adalah PO yang sudah dikirim ke supplier dan menunggu barang datang
+	POStatusSent = "sent"
+	// POStatusPartiallyReceived adalah PO yang sebagian barangnya sudah diterima
+	POStatusPartiallyReceived = "partially_received"
+	// POStatusReceived adalah PO yang seluruh barangnya sudah diterima
+	POStatusReceived = "received"
+	// POStatusCancelled adalah PO yang dibatalkan; barang yang sudah diterima tetap tercatat
+	POStatusCancelled = "cancelled"
+)
+
+var (
+	// ErrPurchaseOrderNotFound dikembalikan jika purchase order dengan id tertentu tidak ada
+	ErrPurchaseOrderNotFound = errors.New("purchase order tidak ditemukan")
+	// ErrInvalidPurchaseOrder dikembalikan jika payload purchase order atau penerimaan tidak valid
+	ErrInvalidPurchaseOrder = errors.New("purchase order tidak valid")
+	// ErrPurchaseOrderStatus dikembalikan jika aksi tidak diizinkan untuk status PO saat ini
+	ErrPurchaseOrderStatus = errors.New("aksi tidak diizinkan untuk status purchase order ini")
+	// ErrOverReceive dikembalikan jika quantity diterima melebihi sisa quantity yang dipesan
+	ErrOverReceive = errors.New("quantity diterima melebihi sisa pesanan")
+)
+
+// PurchaseOrder adalah pesanan pembelian ke supplier. Alur status:
+// draft -> sent -> partially_received -> received, dan draft/sent/partially_received -> cancelled.
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name"`
+	Status       string              `json:"status" enums:"draft,sent,partially_received,received,cancelled"`
+	Note         string              `json:"note"`
+	CreatedBy    string              `json:"created_by"`
+	Total        Money               `json:"total" swaggertype:"number"` // quantity dipesan x unit_cost
+	Lines        []PurchaseOrderLine `json:"lines,omitempty"`
+	Receipts     []GoodsReceipt      `json:"receipts,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	SentAt       *time.Time          `json:"sent_at"`
+}
+
+// PurchaseOrderLine adalah satu produk yang dipesan. product_id menjadi null jika produk dihapus.
+type PurchaseOrderLine struct {
+	ID               int    `json:"id"`
+	ProductID        *int   `json:"product_id"`
+	ProductName      string `json:"product_name"`
+	VariantID        *int   `json:"variant_id,omitempty"`
+	VariantName      string `json:"variant_name,omitempty"` // snapshot nama varian saat PO dibuat
+	QuantityOrdered  int    `json:"quantity_ordered"`
+	QuantityReceived int    `json:"quantity_received"`
+	UnitCost         Money  `json:"unit_cost" swaggertype:"number"`
+	Subtotal         Money  `json:"subtotal" swaggertype:"number"`
+}
+
+// GoodsReceipt adalah satu kali penerimaan barang (bisa sebagian) untuk sebuah PO
+type GoodsReceipt struct {
+	ID         int                `json:"id"`
+	Note       string             `json:"note"`
+	ReceivedBy string             `json:"received_by"`
+	ReceivedAt time.Time          `json:"received_at"`
+	Items      []GoodsReceiptItem `json:"items"`
+}
+
+// GoodsReceiptItem mencatat quantity dan harga pokok aktual yang diterima untuk satu baris PO
+type GoodsReceiptItem struct {
+	LineID      int    `json:"line_id"`
+	ProductID   *int   `json:"product_id"`
+	ProductName string `json:"product_name"`
+	VariantID   *int   `json:"variant_id,omitempty"`
+	VariantName string `json:"variant_name,omitempty"`
+	Quantity    int    `json:"quantity"`
+	UnitCost    Money  `json:"unit_cost" swaggertype:"number"`
+}
+
+type PurchaseOrderFilter struct {
+	Status     string
+	SupplierID int
+}
+
+// PurchaseOrderRequest dipakai untuk membuat PO dan mengubah PO draft
+type PurchaseOrderRequest struct {
+	SupplierID int                        `json:"supplier_id"`
+	Note       string                     `json:"note"`
+	Lines      []PurchaseOrderLineRequest `json:"lines"`
+}
+
+type PurchaseOrderLineRequest struct {
+	ProductID int   `json:"product_id"`
+	VariantID int   `json:"variant_id,omitempty"` // wajib untuk produk bervarian
+	Quantity  int   `json:"quantity"`
+	UnitCost  Money `json:"unit_cost" swaggertype:"number"`
+}
+
+func (r PurchaseOrderRequest) Validate() error {
+	if r.SupplierID <= 0 {
+		return fmt.Errorf("%w: supplier_id wajib diisi", ErrInvalidPurchaseOrder)
+	}
+	if len(r.Lines) == 0 {
+		return fmt.Errorf("%w: lines wajib diisi", ErrInvalidPurchaseOrder)
+	}
+	// Satu produk boleh muncul beberapa kali selama variannya berbeda
+	seen := make(map[[2]int]bool, len(r.Lines))
+	for _, line := range r.Lines {
+		if line.ProductID <= 0 || line.Quantity <= 0 {
+			return fmt.Errorf("%w: product_id dan quantity harus lebih dari 0", ErrInvalidPurchaseOrder)
+		}
+		if line.VariantID < 0 {
+			return fmt.Errorf("%w: variant_id tidak valid", ErrInvalidPurchaseOrder)
+		}
+		if line.UnitCost.IsNegative() {
+			return fmt.Errorf("%w: unit_cost tidak boleh negatif", ErrInvalidPurchaseOrder)
+		}
+		key := [2]int{line.ProductID, line.VariantID}
+		if seen[key] {
+			if line.VariantID > 0 {
+				return fmt.Errorf("%w: varian %d produk %d muncul lebih dari sekali", ErrInvalidPurchaseOrder, line.VariantID, line.ProductID)
+			}
+			return fmt.Errorf("%w: produk %d muncul lebih dari sekali", ErrInvalidPurchaseOrder, line.ProductID)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// ReceiveRequest mencatat barang yang datang; baris yang tidak disebut tidak berubah
+type ReceiveRequest struct {
+	Note  string               `json:"note"`
+	Items []ReceiveItemRequest `json:"items"`
+}
+
+type ReceiveItemRequest struct {
+	LineID   int    `json:"line_id"`
+	Quantity int    `json:"quantity"`
+	UnitCost *Money `json:"unit_cost" swaggertype:"number"` // harga pokok aktual; kosong berarti unit_cost di PO
+}
+
+func (r ReceiveRequest) Validate() error {
+	if len(r.Items) == 0 {
+		return fmt.Errorf("%w: items wajib diisi", ErrInvalidPurchaseOrder)
+	}
+	seen := make(map[int]bool, len(r.Items))
+	for _, item := range r.Items {
+		if item.LineID <= 0 || item.Quantity <= 0 {
+			return fmt.Errorf("%w: line_id dan quantity harus lebih dari 0", ErrInvalidPurchaseOrder)
+		}
+		if item.UnitCost != nil && item.UnitCost.IsNegative() {
+			return fmt.Errorf("%w: unit_cost tidak boleh negatif", ErrInvalidPurchaseOrder)
+		}
+		if seen[item.LineID] {
+			return fmt.Errorf("%w: line %d muncul lebih dari sekali", ErrInvalidPurchaseOrder, item.LineID)
+		}
+		seen[item.LineID] = true
+	}
+	return nil
+}
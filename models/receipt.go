@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ReceiptFormatText   = "txt"
+	ReceiptFormatHTML   = "html"
+	ReceiptFormatESCPOS = "escpos"
+)
+
+var (
+	// ErrUnsupportedReceiptFormat dikembalikan jika format struk tidak dikenal
+	ErrUnsupportedReceiptFormat = errors.New("format struk harus salah satu dari txt, html, escpos")
+	// ErrInvalidPaperWidth dikembalikan jika lebar kertas bukan 58 atau 80 mm
+	ErrInvalidPaperWidth = errors.New("lebar kertas harus 58 atau 80")
+)
+
+// ReceiptStore adalah identitas toko yang dicetak di header/footer struk
+type ReceiptStore struct {
+	Name    string
+	Address string
+	Phone   string
+	Footer  string
+}
+
+// ReceiptTax adalah total pajak per tarif yang dicetak di struk
+type ReceiptTax struct {
+	Name      string
+	Mode      string
+	Amount    Money
+	Inclusive bool
+}
+
+// ReceiptData adalah data yang diberikan ke template struk (txt, html, escpos).
+// Template override di RECEIPT_TEMPLATES_DIR menerima struktur yang sama.
+type ReceiptData struct {
+	Store       ReceiptStore
+	Transaction *Transaction
+	Subtotal    Money // jumlah subtotal baris sebelum diskon
+	Taxes       []ReceiptTax
+	PaperWidth  int // mm
+	Width       int // jumlah karakter per baris (font A): 32 untuk 58mm, 48 untuk 80mm
+	PrintedAt   time.Time
+}
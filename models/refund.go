@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	RefundTypeVoid   = "void"
+	RefundTypeRefund = "refund"
+)
+
+var (
+	// ErrVoidNotAllowed dikembalikan jika void dilakukan untuk transaksi di luar hari ini
+	ErrVoidNotAllowed = errors.New("void hanya bisa dilakukan untuk transaksi hari ini")
+	// ErrTransactionAlreadyVoided dikembalikan jika transaksi sudah pernah di-void
+	ErrTransactionAlreadyVoided = errors.New("transaksi sudah di-void")
+	// ErrNothingToRefund dikembalikan jika seluruh item transaksi sudah di-refund
+	ErrNothingToRefund = errors.New("tidak ada item yang bisa di-refund")
+	// ErrInvalidRefundItem dikembalikan jika item refund tidak valid (detail tidak ada atau quantity <= 0)
+	ErrInvalidRefundItem = errors.New("item refund tidak valid")
+	// ErrRefundExceedsSold dikembalikan jika quantity refund melebihi quantity terjual yang belum di-refund
+	ErrRefundExceedsSold = errors.New("quantity refund melebihi quantity terjual")
+)
+
+type Refund struct {
+	ID            int            `json:"id"`
+	TransactionID int            `json:"transaction_id"`
+	Type          string         `json:"type"`
+	TotalAmount   Money          `json:"total_amount" swaggertype:"number"`
+	Method        string         `json:"method" enums:"cash,qris,debit,credit"` // metode pengembalian uang ke pelanggan
+	Reason        string         `json:"reason"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Details       []RefundDetail `json:"details,omitempty"`
+}
+
+type RefundDetail struct {
+	ID                  int   `json:"id"`
+	RefundID            int   `json:"refund_id"`
+	TransactionDetailID int   `json:"transaction_detail_id"`
+	ProductID           int   `json:"product_id"`
+	Quantity            int   `json:"quantity"`
+	Amount              Money `json:"amount" swaggertype:"number"`
+	TaxAmount           Money `json:"tax_amount" swaggertype:"number"` // porsi pajak di dalam amount
+}
+
+type RefundItem struct {
+	TransactionDetailID int `json:"transaction_detail_id"`
+	Quantity            int `json:"quantity"`
+}
+
+// RefundRequest me-refund sebagian item. Method kosong berarti memakai metode pembayaran terbesar transaksi.
+type RefundRequest struct {
+	Reason string       `json:"reason"`
+	Method string       `json:"method,omitempty" enums:"cash,qris,debit,credit"`
+	Items  []RefundItem `json:"items"`
+}
+
+// VoidRequest membatalkan transaksi. Method kosong berarti memakai metode pembayaran terbesar transaksi.
+type VoidRequest struct {
+	Reason string `json:"reason"`
+	Method string `json:"method,omitempty" enums:"cash,qris,debit,credit"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// StockAlert dicatat saat checkout membuat stok produk turun sampai atau di bawah min_stock.
+// Alert hanya dibuat saat batas dilewati (stok sebelumnya masih di atas min_stock), bukan di setiap penjualan berikutnya.
+type StockAlert struct {
+	ID            int       `json:"id"`
+	ProductID     *int      `json:"product_id"` // null jika produk sudah dihapus
+	ProductName   string    `json:"product_name"`
+	Stock         int       `json:"stock"` // stok setelah checkout
+	MinStock      int       `json:"min_stock"`
+	TransactionID *int      `json:"transaction_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// LowStockProduct adalah produk dengan stok sampai atau di bawah min_stock beserta saran quantity pesan ulang
+type LowStockProduct struct {
+	ProductID           int      `json:"product_id"`
+	ProductName         string   `json:"product_name"`
+	CategoryName        string   `json:"category_name"`
+	Stock               int      `json:"stock"`
+	MinStock            int      `json:"min_stock"`
+	ReorderQty          int      `json:"reorder_qty"`
+	QtySold             int      `json:"qty_sold"`        // terjual bersih (dikurangi refund) selama periode velocity
+	AvgDailySales       float64  `json:"avg_daily_sales"` // qty_sold / jumlah hari periode
+	DaysOfStock         *float64 `json:"days_of_stock"`   // perkiraan hari sampai stok habis, null jika tidak ada penjualan
+	SuggestedReorderQty int      `json:"suggested_reorder_qty"`
+}
+
+// SuggestReorder mengisi avg_daily_sales, days_of_stock dan suggested_reorder_qty dari penjualan velocityDays terakhir.
+// Saran pesan ulang cukup untuk coverDays ke depan di atas min_stock, minimal reorder_qty:
+//
+//	suggested = max(reorder_qty, ceil(avg_daily_sales x coverDays) + min_stock - stock)
+func (p *LowStockProduct) SuggestReorder(velocityDays, coverDays int) {
+	if velocityDays > 0 && p.QtySold > 0 {
+		p.AvgDailySales = math.Round(float64(p.QtySold)/float64(velocityDays)*100) / 100
+	}
+
+	p.DaysOfStock = nil
+	if p.AvgDailySales > 0 {
+		days := math.Round(float64(max(p.Stock, 0))/p.AvgDailySales*10) / 10
+		p.DaysOfStock = &days
+	}
+
+	need := int(math.Ceil(float64(p.QtySold)*float64(coverDays)/float64(max(velocityDays, 1)))) + p.MinStock - p.Stock
+	p.SuggestedReorderQty = max(need, p.ReorderQty, 0)
+}
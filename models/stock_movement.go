@@ -0,0 +1,115 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// StockReasonSale adalah pengurangan stok karena checkout; reference_id = id transaksi
+	StockReasonSale = "sale"
+	// StockReasonRefund adalah pengembalian stok karena refund/void; reference_id = id refund
+	StockReasonRefund = "refund"
+	// StockReasonAdjustment adalah koreksi stok manual (edit produk, stock adjustment, stock-take);
+	// reference_id = id stock-take jika berasal dari stock-take
+	StockReasonAdjustment = "adjustment"
+	// StockReasonReceiving adalah penambahan stok dari penerimaan barang
+	StockReasonReceiving = "receiving"
+	// StockReasonInitial adalah stok awal saat produk dibuat
+	StockReasonInitial = "initial"
+)
+
+const (
+	// Kode alasan stock adjustment, disimpan di awal note baris ledger
+	AdjustmentCodeDamaged    = "damaged"
+	AdjustmentCodeExpired    = "expired"
+	AdjustmentCodeLost       = "lost"
+	AdjustmentCodeFound      = "found"
+	AdjustmentCodeCorrection = "correction"
+	AdjustmentCodeOther      = "other"
+)
+
+var (
+	// ErrInvalidStockAdjustment dikembalikan jika payload stock adjustment tidak valid
+	ErrInvalidStockAdjustment = errors.New("stock adjustment tidak valid")
+	// ErrNegativeStock dikembalikan jika perubahan stok membuat stok produk menjadi negatif
+	ErrNegativeStock = errors.New("stok tidak boleh negatif")
+)
+
+// DefaultActor dipakai jika request tidak menyertakan header X-Actor
+const DefaultActor = "anonymous"
+
+// StockMovement adalah satu baris ledger perubahan products.stock
+type StockMovement struct {
+	ID           int       `json:"id"`
+	ProductID    int       `json:"product_id"`
+	ProductName  string    `json:"product_name"`         // snapshot nama produk saat stok berubah
+	VariantID    *int      `json:"variant_id,omitempty"` // varian yang stoknya berubah; balance_after tetap stok total produk
+	Reason       string    `json:"reason" enums:"sale,refund,adjustment,receiving,initial"`
+	Quantity     int       `json:"quantity"`      // delta: negatif untuk stok keluar
+	BalanceAfter int       `json:"balance_after"` // stok produk setelah perubahan ini
+	ReferenceID  *int      `json:"reference_id"`
+	Actor        string    `json:"actor"`
+	Note         string    `json:"note,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// StockMovementFilter berisi filter dan pagination ledger stok satu produk
+type StockMovementFilter struct {
+	Reason    string
+	StartDate string // format YYYY-MM-DD, inklusif
+	EndDate   string // format YYYY-MM-DD, inklusif
+	Page      int
+	Limit     int
+}
+
+// IsValidStockReason memeriksa apakah reason ledger stok dikenal
+func IsValidStockReason(reason string) bool {
+	switch reason {
+	case StockReasonSale, StockReasonRefund, StockReasonAdjustment, StockReasonReceiving, StockReasonInitial:
+		return true
+	default:
+		return false
+	}
+}
+
+// StockAdjustmentRequest adalah koreksi stok satu produk tanpa mengubah data produk lainnya
+type StockAdjustmentRequest struct {
+	VariantID  int    `json:"variant_id,omitempty"`  // wajib untuk produk bervarian
+	Quantity   int    `json:"quantity" example:"-2"` // delta: negatif untuk mengurangi stok
+	ReasonCode string `json:"reason_code" enums:"damaged,expired,lost,found,correction,other"`
+	Note       string `json:"note"`
+}
+
+func (r StockAdjustmentRequest) Validate() error {
+	if r.Quantity == 0 {
+		return fmt.Errorf("%w: quantity tidak boleh 0", ErrInvalidStockAdjustment)
+	}
+	if r.VariantID < 0 {
+		return fmt.Errorf("%w: variant_id tidak valid", ErrInvalidStockAdjustment)
+	}
+	switch r.ReasonCode {
+	case AdjustmentCodeDamaged, AdjustmentCodeExpired, AdjustmentCodeLost,
+		AdjustmentCodeFound, AdjustmentCodeCorrection, AdjustmentCodeOther:
+	default:
+		return fmt.Errorf("%w: reason_code harus salah satu dari damaged, expired, lost, found, correction, other", ErrInvalidStockAdjustment)
+	}
+	if r.ReasonCode == AdjustmentCodeOther && strings.TrimSpace(r.Note) == "" {
+		return fmt.Errorf("%w: note wajib diisi untuk reason_code other", ErrInvalidStockAdjustment)
+	}
+	if len(r.Note) > 200 {
+		return fmt.Errorf("%w: note maksimal 200 karakter", ErrInvalidStockAdjustment)
+	}
+	return nil
+}
+
+// LedgerNote menggabungkan kode alasan dan catatan untuk kolom note di ledger stok
+func (r StockAdjustmentRequest) LedgerNote() string {
+	note := strings.TrimSpace(r.Note)
+	if note == "" {
+		return r.ReasonCode
+	}
+	return r.ReasonCode + ": " + note
+}
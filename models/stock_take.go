@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// StockTakeStatusOpen adalah sesi stock-take yang masih menerima hasil hitung
+	StockTakeStatusOpen = "open"
+	// StockTakeStatusCommitted adalah sesi yang selisihnya sudah diposting ke stok produk
+	StockTakeStatusCommitted = "committed"
+	// StockTakeStatusCancelled adalah sesi yang dibatalkan tanpa mengubah stok
+	StockTakeStatusCancelled = "cancelled"
+)
+
+var (
+	// ErrStockTakeNotFound dikembalikan jika sesi stock-take dengan id tertentu tidak ada
+	ErrStockTakeNotFound = errors.New("stock-take tidak ditemukan")
+	// ErrStockTakeNotOpen dikembalikan jika sesi yang sudah di-commit atau dibatalkan diubah
+	ErrStockTakeNotOpen = errors.New("stock-take tidak dalam status open")
+	// ErrStockTakeEmpty dikembalikan jika sesi tanpa hasil hitung di-commit
+	ErrStockTakeEmpty = errors.New("stock-take belum memiliki hasil hitung")
+)
+
+// StockTake adalah sesi penghitungan stok fisik. system_quantity setiap item adalah stok sistem saat
+// produk dihitung; saat commit selisihnya ditambahkan ke stok saat ini sebagai adjustment.
+type StockTake struct {
+	ID          int               `json:"id"`
+	Note        string            `json:"note"`
+	Status      string            `json:"status" enums:"open,committed,cancelled"`
+	CreatedBy   string            `json:"created_by"`
+	CommittedBy *string           `json:"committed_by"`
+	CreatedAt   time.Time         `json:"created_at"`
+	ClosedAt    *time.Time        `json:"closed_at"` // waktu commit atau pembatalan
+	Items       []StockTakeItem   `json:"items,omitempty"`
+	Summary     *StockTakeSummary `json:"summary,omitempty"`
+}
+
+// StockTakeItem adalah hasil hitung satu produk (atau satu varian) beserta selisihnya terhadap stok sistem
+type StockTakeItem struct {
+	ProductID       *int      `json:"product_id"` // null jika produk sudah di-purge
+	ProductName     string    `json:"product_name"`
+	VariantID       *int      `json:"variant_id,omitempty"`
+	VariantName     string    `json:"variant_name,omitempty"`
+	SystemQuantity  int       `json:"system_quantity"`
+	CountedQuantity int       `json:"counted_quantity"`
+	Variance        int       `json:"variance"`                            // counted - system
+	VarianceValue   Money     `json:"variance_value" swaggertype:"number"` // variance x harga varian/produk saat ini
+	CountedAt       time.Time `json:"counted_at"`
+}
+
+// StockTakeSummary adalah ringkasan laporan selisih satu sesi stock-take
+type StockTakeSummary struct {
+	ItemsCounted       int   `json:"items_counted"`
+	ItemsWithVariance  int   `json:"items_with_variance"`
+	TotalVariance      int   `json:"total_variance"`
+	TotalVarianceValue Money `json:"total_variance_value" swaggertype:"number"`
+}
+
+type CreateStockTakeRequest struct {
+	Note string `json:"note"`
+}
+
+// StockTakeCountRequest berisi hasil hitung banyak produk sekaligus; hitungan ulang produk yang sama menimpa nilai sebelumnya
+type StockTakeCountRequest struct {
+	Items []StockTakeCount `json:"items"`
+}
+
+// StockTakeCount adalah hasil hitung satu produk; produk bervarian dihitung per varian
+type StockTakeCount struct {
+	ProductID       int `json:"product_id"`
+	VariantID       int `json:"variant_id,omitempty"`
+	CountedQuantity int `json:"counted_quantity"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSupplierNotFound dikembalikan jika supplier dengan id tertentu tidak ada
+	ErrSupplierNotFound = errors.New("supplier tidak ditemukan")
+	// ErrInvalidSupplier dikembalikan jika payload supplier tidak valid
+	ErrInvalidSupplier = errors.New("supplier tidak valid")
+	// ErrSupplierInUse dikembalikan jika supplier yang sudah memiliki purchase order dihapus
+	ErrSupplierInUse = errors.New("supplier masih dipakai purchase order")
+)
+
+// Supplier adalah pemasok barang untuk purchase order
+type Supplier struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone"`
+	Email     string    `json:"email"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s Supplier) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("%w: name wajib diisi", ErrInvalidSupplier)
+	}
+	if len(s.Name) > 100 {
+		return fmt.Errorf("%w: name maksimal 100 karakter", ErrInvalidSupplier)
+	}
+	if s.Email != "" && !strings.Contains(s.Email, "@") {
+		return fmt.Errorf("%w: email tidak valid", ErrInvalidSupplier)
+	}
+	return nil
+}
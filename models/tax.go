@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// TaxModeExclusive: harga belum termasuk pajak, pajak ditambahkan di atas harga
+	TaxModeExclusive = "exclusive"
+	// TaxModeInclusive: harga sudah termasuk pajak, pajak diambil dari dalam harga
+	TaxModeInclusive = "inclusive"
+)
+
+var (
+	// ErrTaxRateNotFound dikembalikan jika tarif pajak dengan id tertentu tidak ada
+	ErrTaxRateNotFound = errors.New("tarif pajak tidak ditemukan")
+	// ErrInvalidTaxRate dikembalikan jika payload tarif pajak tidak valid
+	ErrInvalidTaxRate = errors.New("tarif pajak tidak valid")
+)
+
+// TaxRate adalah tarif pajak (mis. PPN 11%) yang bisa dipasang per kategori atau per produk.
+// Tarif pada produk mengalahkan tarif pada kategorinya.
+type TaxRate struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	RateBP int    `json:"rate_bp"` // basis point, 1100 = 11%
+	Mode   string `json:"mode" enums:"exclusive,inclusive"`
+	Active bool   `json:"active"`
+}
+
+func (t TaxRate) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("%w: name wajib diisi", ErrInvalidTaxRate)
+	}
+	if t.RateBP < 0 || t.RateBP > 10000 {
+		return fmt.Errorf("%w: rate_bp harus antara 0 dan 10000", ErrInvalidTaxRate)
+	}
+	if t.Mode != TaxModeExclusive && t.Mode != TaxModeInclusive {
+		return fmt.Errorf("%w: mode harus exclusive atau inclusive", ErrInvalidTaxRate)
+	}
+	return nil
+}
+
+// Calculate menghitung pajak dari base (nilai baris setelah diskon) dan nilai akhir yang dibayar.
+//
+//   - exclusive: tax = base x rate, total = base + tax
+//   - inclusive: tax = base x rate / (1 + rate), total = base
+//
+// Pembulatan dilakukan per baris ke minor unit (half away from zero), lalu total transaksi
+// adalah jumlah pajak per baris, sehingga hasilnya selalu sama untuk input yang sama.
+func (t TaxRate) Calculate(base Money) (tax Money, total Money) {
+	if t.RateBP <= 0 {
+		return NewMoney(0), base
+	}
+
+	if t.Mode == TaxModeInclusive {
+		tax = base.MulRatio(int64(t.RateBP), int64(10000+t.RateBP))
+		return tax, base
+	}
+
+	tax = base.Percent(int64(t.RateBP))
+	return tax, base.Add(tax)
+}
+
+// TaxReportLine adalah ringkasan pajak per tarif pada suatu periode
+type TaxReportLine struct {
+	TaxName       string `json:"tax_name"`
+	RateBP        int    `json:"rate_bp"`
+	Mode          string `json:"mode"`
+	TaxableAmount Money  `json:"taxable_amount" swaggertype:"number"` // DPP: nilai baris setelah diskon, tanpa pajak
+	TaxAmount     Money  `json:"tax_amount" swaggertype:"number"`
+	RefundedTax   Money  `json:"refunded_tax" swaggertype:"number"`
+	NetTax        Money  `json:"net_tax" swaggertype:"number"`
+}
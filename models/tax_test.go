@@ -0,0 +1,73 @@
+package models
+
+import "testing"
+
+func TestTaxRateCalculate(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      TaxRate
+		base      string
+		wantTax   string
+		wantTotal string
+	}{
+		{name: "exclusive", rate: TaxRate{RateBP: 1100, Mode: TaxModeExclusive}, base: "10000", wantTax: "1100.00", wantTotal: "11100.00"},
+		{name: "exclusive half minor unit rounds up", rate: TaxRate{RateBP: 1100, Mode: TaxModeExclusive}, base: "0.50", wantTax: "0.06", wantTotal: "0.56"},
+		{name: "exclusive below half rounds down", rate: TaxRate{RateBP: 1100, Mode: TaxModeExclusive}, base: "0.40", wantTax: "0.04", wantTotal: "0.44"},
+		{name: "exclusive negative refund base", rate: TaxRate{RateBP: 1100, Mode: TaxModeExclusive}, base: "-0.50", wantTax: "-0.06", wantTotal: "-0.56"},
+		{name: "inclusive", rate: TaxRate{RateBP: 1100, Mode: TaxModeInclusive}, base: "11100", wantTax: "1100.00", wantTotal: "11100.00"},
+		{name: "inclusive rounds down", rate: TaxRate{RateBP: 1100, Mode: TaxModeInclusive}, base: "10000", wantTax: "990.99", wantTotal: "10000.00"},
+		{name: "inclusive half minor unit rounds up", rate: TaxRate{RateBP: 2000, Mode: TaxModeInclusive}, base: "0.03", wantTax: "0.01", wantTotal: "0.03"},
+		{name: "inclusive negative refund base", rate: TaxRate{RateBP: 2000, Mode: TaxModeInclusive}, base: "-0.03", wantTax: "-0.01", wantTotal: "-0.03"},
+		{name: "zero rate exclusive", rate: TaxRate{RateBP: 0, Mode: TaxModeExclusive}, base: "12500.50", wantTax: "0.00", wantTotal: "12500.50"},
+		{name: "zero rate inclusive", rate: TaxRate{RateBP: 0, Mode: TaxModeInclusive}, base: "12500.50", wantTax: "0.00", wantTotal: "12500.50"},
+		{name: "zero base", rate: TaxRate{RateBP: 1100, Mode: TaxModeExclusive}, base: "0", wantTax: "0.00", wantTotal: "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tax, total := tt.rate.Calculate(mustParseMoney(t, tt.base))
+			if tax.String() != tt.wantTax || total.String() != tt.wantTotal {
+				t.Errorf("Calculate(%s) = (%s, %s), want (%s, %s)", tt.base, tax, total, tt.wantTax, tt.wantTotal)
+			}
+		})
+	}
+}
+
+// Pajak dibulatkan per baris lalu dijumlahkan, sehingga total bisa berbeda dari pajak atas jumlah base
+func TestTaxRateCalculateMultiLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      TaxRate
+		lines     []string
+		wantTax   string
+		wantTotal string
+	}{
+		{name: "exclusive rounds per line", rate: TaxRate{RateBP: 1100, Mode: TaxModeExclusive}, lines: []string{"0.50", "0.50"}, wantTax: "0.12", wantTotal: "1.12"},
+		{name: "exclusive mixed lines", rate: TaxRate{RateBP: 1100, Mode: TaxModeExclusive}, lines: []string{"10000", "2500.50", "0.40"}, wantTax: "1375.10", wantTotal: "13876.00"},
+		{name: "inclusive rounds per line", rate: TaxRate{RateBP: 2000, Mode: TaxModeInclusive}, lines: []string{"0.03", "0.03", "0.03"}, wantTax: "0.03", wantTotal: "0.09"},
+		{name: "sale with refund line", rate: TaxRate{RateBP: 1100, Mode: TaxModeExclusive}, lines: []string{"0.50", "-0.50"}, wantTax: "0.00", wantTotal: "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taxSum, totalSum := NewMoney(0), NewMoney(0)
+			for _, line := range tt.lines {
+				tax, total := tt.rate.Calculate(mustParseMoney(t, line))
+				taxSum = taxSum.Add(tax)
+				totalSum = totalSum.Add(total)
+			}
+			if taxSum.String() != tt.wantTax || totalSum.String() != tt.wantTotal {
+				t.Errorf("Calculate(%v) sums = (%s, %s), want (%s, %s)", tt.lines, taxSum, totalSum, tt.wantTax, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func mustParseMoney(t *testing.T, s string) Money {
+	t.Helper()
+	m, err := ParseMoney(s)
+	if err != nil {
+		t.Fatalf("ParseMoney(%q): %v", s, err)
+	}
+	return m
+}
@@ -1,28 +1,105 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrTransactionNotFound dikembalikan jika transaksi dengan id tertentu tidak ada
+var ErrTransactionNotFound = errors.New("transaksi tidak ditemukan")
 
 type Transaction struct {
-	ID          int                 `json:"id"`
-	TotalAmount int                 `json:"total_amount"`
-	CreatedAt   time.Time           `json:"created_at"`
-	Details     []TransactionDetail `json:"details"`
+	ID             int                 `json:"id"`
+	TotalAmount    Money               `json:"total_amount" swaggertype:"number"` // total dibayar: setelah diskon, termasuk pajak
+	DiscountAmount Money               `json:"discount_amount" swaggertype:"number"`
+	TaxAmount      Money               `json:"tax_amount" swaggertype:"number"`
+	AmountPaid     Money               `json:"amount_paid" swaggertype:"number"`
+	ChangeAmount   Money               `json:"change_amount" swaggertype:"number"`
+	CreatedAt      time.Time           `json:"created_at"`
+	Details        []TransactionDetail `json:"details,omitempty"`
+	Payments       []Payment           `json:"payments,omitempty"`
+	Refunds        []Refund            `json:"refunds,omitempty"`
 }
 
 type TransactionDetail struct {
-	ID            int    `json:"id"`
-	TransactionID int    `json:"transaction_id"`
-	ProductID     int    `json:"product_id"`
-	ProductName   string `json:"product_name,omitempty"`
-	Quantity      int    `json:"quantity"`
-	Subtotal      int    `json:"subtotal"`
+	ID             int            `json:"id"`
+	TransactionID  int            `json:"transaction_id"`
+	ProductID      int            `json:"product_id"`
+	ProductName    string         `json:"product_name,omitempty"`
+	VariantID      *int           `json:"variant_id,omitempty"`
+	VariantName    string         `json:"variant_name,omitempty"` // snapshot nama varian saat checkout, mis. "M / Merah"
+	UnitPrice      Money          `json:"unit_price" swaggertype:"number"`
+	UnitCost       Money          `json:"unit_cost" swaggertype:"number"` // snapshot harga pokok produk saat checkout
+	Quantity       int            `json:"quantity"`
+	Subtotal       Money          `json:"subtotal" swaggertype:"number"` // unit_price x quantity sebelum diskon
+	DiscountAmount Money          `json:"discount_amount" swaggertype:"number"`
+	Discounts      []LineDiscount `json:"discounts,omitempty"`
+	TaxName        string         `json:"tax_name,omitempty"`
+	TaxRateBP      int            `json:"tax_rate_bp"`
+	TaxMode        string         `json:"tax_mode,omitempty"`
+	TaxAmount      Money          `json:"tax_amount" swaggertype:"number"`
+	Total          Money          `json:"total" swaggertype:"number"` // subtotal - diskon (+ pajak jika exclusive)
+	RefundedQty    int            `json:"refunded_quantity,omitempty"`
 }
 
+// CheckoutItem menunjuk produk lewat product_id atau barcode hasil scan (salah satu).
+// Produk bervarian wajib menyertakan variant_id; product_id boleh dikosongkan jika variant_id diisi.
 type CheckoutItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int    `json:"product_id,omitempty"`
+	VariantID int    `json:"variant_id,omitempty"`
+	Barcode   string `json:"barcode,omitempty" example:"8992761111113"`
+	Quantity  int    `json:"quantity"`
 }
 
 type CheckoutRequest struct {
-	Items []CheckoutItem `json:"items"`
+	Items        []CheckoutItem    `json:"items"`
+	VoucherCodes []string          `json:"voucher_codes,omitempty"`
+	Payments     []CheckoutPayment `json:"payments,omitempty"` // kosong berarti dibayar tunai pas
+}
+
+// SalesTotals adalah agregat penjualan pada suatu periode
+type SalesTotals struct {
+	GrossRevenue   Money // sebelum diskon
+	TotalDiscount  Money
+	TotalRefund    Money
+	NetRevenue     Money // gross - diskon - refund
+	TotalTransaksi int
+	Payments       []PaymentMethodTotal
+}
+
+// TransactionFilter berisi filter dan pagination untuk daftar transaksi.
+// Field bernilai kosong/nil berarti filter tersebut tidak dipakai.
+type TransactionFilter struct {
+	StartDate string // format YYYY-MM-DD, inklusif
+	EndDate   string // format YYYY-MM-DD, inklusif
+	MinAmount *Money
+	MaxAmount *Money
+	ProductID int // hanya transaksi yang memuat produk ini
+	Page      int
+	Limit     int
+}
+
+// InsufficientStockItem menjelaskan satu produk yang stoknya tidak cukup saat checkout
+type InsufficientStockItem struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	VariantID   *int   `json:"variant_id,omitempty"`
+	VariantName string `json:"variant_name,omitempty"`
+	Requested   int    `json:"requested"`
+	Available   int    `json:"available"`
+}
+
+// ErrInsufficientStock dikembalikan checkout jika quantity melebihi stok yang tersedia
+type ErrInsufficientStock struct {
+	Items []InsufficientStockItem
+}
+
+func (e *ErrInsufficientStock) Error() string {
+	parts := make([]string, 0, len(e.Items))
+	for _, item := range e.Items {
+		parts = append(parts, fmt.Sprintf("%s (id %d): diminta %d, tersedia %d", item.ProductName, item.ProductID, item.Requested, item.Available))
+	}
+	return "stok tidak mencukupi: " + strings.Join(parts, "; ")
 }
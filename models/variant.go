@@ -0,0 +1,112 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrVariantNotFound dikembalikan jika varian tidak ada atau bukan milik produk yang diminta
+	ErrVariantNotFound = errors.New("varian tidak ditemukan")
+	// ErrOptionTypeNotFound dikembalikan jika tipe opsi tidak ada atau bukan milik produk yang diminta
+	ErrOptionTypeNotFound = errors.New("tipe opsi tidak ditemukan")
+	// ErrInvalidVariant dikembalikan jika payload tipe opsi atau varian tidak valid
+	ErrInvalidVariant = errors.New("varian tidak valid")
+	// ErrDuplicateVariant dikembalikan jika kombinasi opsi sudah dipakai varian lain pada produk yang sama
+	ErrDuplicateVariant = errors.New("kombinasi opsi sudah dipakai varian lain")
+	// ErrOptionTypeInUse dikembalikan jika tipe opsi dihapus atau ditambah saat produk sudah memiliki varian
+	ErrOptionTypeInUse = errors.New("tipe opsi tidak bisa diubah karena produk sudah memiliki varian")
+	// ErrVariantRequired dikembalikan checkout jika produk bervarian dipesan tanpa variant_id
+	ErrVariantRequired = errors.New("produk memiliki varian, variant_id wajib diisi")
+	// ErrProductHasVariants dikembalikan jika stok produk bervarian diubah di level produk
+	ErrProductHasVariants = errors.New("stok produk bervarian diatur per varian")
+)
+
+// ProductOptionType adalah dimensi varian sebuah produk, mis. Ukuran atau Warna
+type ProductOptionType struct {
+	ID        int                  `json:"id"`
+	ProductID int                  `json:"product_id"`
+	Name      string               `json:"name" example:"Ukuran"`
+	Position  int                  `json:"position"`
+	Values    []ProductOptionValue `json:"values"`
+}
+
+type ProductOptionValue struct {
+	ID           int    `json:"id"`
+	OptionTypeID int    `json:"option_type_id"`
+	Value        string `json:"value" example:"M"`
+	Position     int    `json:"position"`
+}
+
+// ProductVariant adalah satu kombinasi nilai opsi dengan SKU, harga dan stok sendiri.
+// Stok produk induk selalu sama dengan jumlah stok seluruh variannya.
+type ProductVariant struct {
+	ID             int               `json:"id"`
+	ProductID      int               `json:"product_id"`
+	Name           string            `json:"name" example:"M / Merah"` // nilai opsi digabung sesuai urutan tipe opsi
+	SKU            string            `json:"sku" example:"KAOS-M-MRH"`
+	Price          *Money            `json:"price" swaggertype:"number"`           // null berarti memakai harga produk induk
+	EffectivePrice Money             `json:"effective_price" swaggertype:"number"` // harga yang dipakai saat checkout
+	Stock          int               `json:"stock"`
+	Options        map[string]string `json:"options"` // nama tipe opsi -> nilai
+}
+
+// OptionTypeRequest membuat tipe opsi beserta nilainya, atau menambah nilai ke tipe opsi yang sudah ada
+type OptionTypeRequest struct {
+	Name   string   `json:"name" example:"Ukuran"`
+	Values []string `json:"values" example:"S,M,L"`
+}
+
+func (r *OptionTypeRequest) Validate(requireName bool) error {
+	r.Name = strings.TrimSpace(r.Name)
+	if requireName && r.Name == "" {
+		return fmt.Errorf("%w: name wajib diisi", ErrInvalidVariant)
+	}
+	if len(r.Name) > 50 {
+		return fmt.Errorf("%w: name maksimal 50 karakter", ErrInvalidVariant)
+	}
+	if len(r.Values) == 0 {
+		return fmt.Errorf("%w: values wajib diisi", ErrInvalidVariant)
+	}
+
+	seen := make(map[string]bool, len(r.Values))
+	for i, v := range r.Values {
+		v = strings.TrimSpace(v)
+		key := strings.ToLower(v)
+		if v == "" || len(v) > 50 {
+			return fmt.Errorf("%w: value harus 1-50 karakter", ErrInvalidVariant)
+		}
+		if seen[key] {
+			return fmt.Errorf("%w: value %q duplikat", ErrInvalidVariant, v)
+		}
+		seen[key] = true
+		r.Values[i] = v
+	}
+	return nil
+}
+
+// VariantRequest adalah payload membuat/mengubah varian. Options berisi satu nilai untuk setiap tipe opsi produk.
+type VariantRequest struct {
+	SKU     string            `json:"sku" example:"KAOS-M-MRH"`
+	Price   *Money            `json:"price" swaggertype:"number"` // kosong berarti memakai harga produk induk
+	Stock   int               `json:"stock"`
+	Options map[string]string `json:"options"`
+}
+
+func (r *VariantRequest) Validate() error {
+	r.SKU = strings.TrimSpace(r.SKU)
+	if len(r.SKU) > maxSKULength || strings.ContainsAny(r.SKU, " \t\n") {
+		return fmt.Errorf("%w: maksimal %d karakter tanpa spasi", ErrInvalidSKU, maxSKULength)
+	}
+	if r.Price != nil && r.Price.IsNegative() {
+		return fmt.Errorf("%w: price tidak boleh negatif", ErrInvalidVariant)
+	}
+	if r.Stock < 0 {
+		return fmt.Errorf("%w: stock tidak boleh negatif", ErrInvalidVariant)
+	}
+	if len(r.Options) == 0 {
+		return fmt.Errorf("%w: options wajib diisi", ErrInvalidVariant)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrPreconditionRequired dikembalikan jika PUT/PATCH/DELETE dikirim tanpa header If-Match
+var ErrPreconditionRequired = errors.New("header If-Match wajib diisi dengan ETag data terbaru")
+
+// ErrVersionMismatch dikembalikan jika If-Match tidak cocok dengan versi data saat ini,
+// artinya data sudah diubah request lain sejak terakhir dibaca
+type ErrVersionMismatch struct {
+	Current int
+}
+
+func (e *ErrVersionMismatch) Error() string {
+	return fmt.Sprintf("data sudah diubah request lain (versi saat ini %d), ambil ulang data lalu kirim If-Match terbaru", e.Current)
+}
+
+// IfMatch adalah isi header If-Match: daftar versi yang diterima, atau Any untuk "*"
+type IfMatch struct {
+	Any      bool
+	Versions []int
+}
+
+// Check mengembalikan ErrVersionMismatch jika versi saat ini tidak ada di daftar If-Match
+func (m IfMatch) Check(current int) error {
+	if m.Any {
+		return nil
+	}
+	for _, v := range m.Versions {
+		if v == current {
+			return nil
+		}
+	}
+	return &ErrVersionMismatch{Current: current}
+}
+
+// ETag mengubah versi data menjadi strong ETag, mis. "3"
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
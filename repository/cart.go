@@ -0,0 +1,405 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"simple-crud/models"
+)
+
+type CartRepository struct {
+	db *sql.DB
+}
+
+func NewCartRepository(db *sql.DB) *CartRepository {
+	return &CartRepository{db: db}
+}
+
+// GetAll mengembalikan keranjang beserta item, terbaru lebih dulu. status kosong berarti semua status.
+func (r *CartRepository) GetAll(status string) ([]models.Cart, error) {
+	where := ""
+	args := []any{}
+	if status != "" {
+		where = " WHERE c.status = $1"
+		args = append(args, status)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT c.id, c.name, c.status, c.transaction_id, c.created_at, c.updated_at
+		FROM carts c`+where+`
+		ORDER BY c.updated_at DESC, c.id DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carts := make([]models.Cart, 0)
+	index := make(map[int]int)
+	for rows.Next() {
+		var c models.Cart
+		if err := rows.Scan(&c.ID, &c.Name, &c.Status, &c.TransactionID, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		c.Items = make([]models.CartItem, 0)
+		index[c.ID] = len(carts)
+		carts = append(carts, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = r.loadCartItems(" JOIN carts c ON c.id = ci.cart_id"+where, args, func(cartID int, item models.CartItem) {
+		if i, ok := index[cartID]; ok {
+			carts[i].Items = append(carts[i].Items, item)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range carts {
+		summarizeCart(&carts[i])
+	}
+
+	return carts, nil
+}
+
+func (r *CartRepository) GetByID(id int) (*models.Cart, error) {
+	var c models.Cart
+	err := r.db.QueryRow("SELECT id, name, status, transaction_id, created_at, updated_at FROM carts WHERE id = $1", id).
+		Scan(&c.ID, &c.Name, &c.Status, &c.TransactionID, &c.CreatedAt, &c.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrCartNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.Items = make([]models.CartItem, 0)
+	err = r.loadCartItems(" WHERE ci.cart_id = $1", []any{id}, func(_ int, item models.CartItem) {
+		c.Items = append(c.Items, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summarizeCart(&c)
+	return &c, nil
+}
+
+// loadCartItems membaca item keranjang dengan harga dan stok produk (atau varian) saat ini
+func (r *CartRepository) loadCartItems(filter string, args []any, add func(cartID int, item models.CartItem)) error {
+	rows, err := r.db.Query(`
+		SELECT ci.cart_id, p.id, p.name, ci.variant_id, CASE WHEN v.id IS NULL THEN '' ELSE `+variantNameSQL+` END,
+			COALESCE(v.price, p.price), ci.quantity, COALESCE(v.stock, p.stock)
+		FROM cart_items ci
+		JOIN products p ON p.id = ci.product_id
+		LEFT JOIN product_variants v ON v.id = ci.variant_id`+filter+`
+		ORDER BY ci.cart_id, ci.id
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cartID int
+		var item models.CartItem
+		if err := rows.Scan(&cartID, &item.ProductID, &item.ProductName, &item.VariantID, &item.VariantName,
+			&item.UnitPrice, &item.Quantity, &item.Stock); err != nil {
+			return err
+		}
+		item.Subtotal = item.UnitPrice.Mul(int64(item.Quantity))
+		item.StockOK = item.Quantity <= item.Stock
+		add(cartID, item)
+	}
+
+	return rows.Err()
+}
+
+func summarizeCart(c *models.Cart) {
+	c.Subtotal = models.NewMoney(0)
+	c.StockOK = true
+	for _, item := range c.Items {
+		c.Subtotal = c.Subtotal.Add(item.Subtotal)
+		if !item.StockOK {
+			c.StockOK = false
+		}
+	}
+}
+
+func (r *CartRepository) Create(name string) (int, error) {
+	var id int
+	err := r.db.QueryRow("INSERT INTO carts (name, status) VALUES ($1, $2) RETURNING id", name, models.CartStatusOpen).Scan(&id)
+	return id, err
+}
+
+// Delete menghapus keranjang beserta itemnya
+func (r *CartRepository) Delete(id int) error {
+	res, err := r.db.Exec("DELETE FROM carts WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrCartNotFound
+	}
+	return nil
+}
+
+// DeleteExpired menghapus keranjang yang tidak diubah lebih lama dari ttl dan mengembalikan jumlahnya
+func (r *CartRepository) DeleteExpired(ttl time.Duration) (int64, error) {
+	res, err := r.db.Exec("DELETE FROM carts WHERE updated_at < NOW() - make_interval(secs => $1)", ttl.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// cartItemMatch mencocokkan baris cart_items dengan produk dan varian ($3, 0 berarti tanpa varian)
+const cartItemMatch = "cart_id = $1 AND product_id = $2 AND COALESCE(variant_id, 0) = $3"
+
+// AddItem menambah quantity produk/varian di keranjang (atau membuat baris baru) selama stok saat ini mencukupi.
+// variantID 0 berarti produk tanpa varian.
+func (r *CartRepository) AddItem(cartID, productID, variantID, quantity int) error {
+	return r.withOpenCart(cartID, func(tx *sql.Tx) error {
+		var current int
+		err := tx.QueryRow("SELECT quantity FROM cart_items WHERE "+cartItemMatch, cartID, productID, variantID).Scan(&current)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := checkCartStock(tx, productID, variantID, current+quantity); err != nil {
+			return err
+		}
+
+		// Keranjang sudah di-lock, jadi cukup update baris yang ada atau insert baris baru
+		if current > 0 {
+			_, err = tx.Exec("UPDATE cart_items SET quantity = quantity + $4 WHERE "+cartItemMatch, cartID, productID, variantID, quantity)
+			return err
+		}
+		_, err = tx.Exec("INSERT INTO cart_items (cart_id, product_id, variant_id, quantity) VALUES ($1, $2, $3, $4)",
+			cartID, productID, variantRef(variantID), quantity)
+		return err
+	})
+}
+
+// SetItemQuantity mengganti quantity produk/varian yang sudah ada di keranjang
+func (r *CartRepository) SetItemQuantity(cartID, productID, variantID, quantity int) error {
+	return r.withOpenCart(cartID, func(tx *sql.Tx) error {
+		if err := checkCartStock(tx, productID, variantID, quantity); err != nil {
+			return err
+		}
+
+		res, err := tx.Exec("UPDATE cart_items SET quantity = $4 WHERE "+cartItemMatch, cartID, productID, variantID, quantity)
+		if err != nil {
+			return err
+		}
+		return requireCartItem(res)
+	})
+}
+
+func (r *CartRepository) RemoveItem(cartID, productID, variantID int) error {
+	return r.withOpenCart(cartID, func(tx *sql.Tx) error {
+		res, err := tx.Exec("DELETE FROM cart_items WHERE "+cartItemMatch, cartID, productID, variantID)
+		if err != nil {
+			return err
+		}
+		return requireCartItem(res)
+	})
+}
+
+// Hold memarkir keranjang open; name (jika diisi) menggantikan label keranjang
+func (r *CartRepository) Hold(cartID int, name string) error {
+	return r.withOpenCart(cartID, func(tx *sql.Tx) error {
+		_, err := tx.Exec("UPDATE carts SET status = $1, name = COALESCE(NULLIF($2, ''), name) WHERE id = $3",
+			models.CartStatusHeld, name, cartID)
+		return err
+	})
+}
+
+// Resume membuka kembali keranjang yang di-hold
+func (r *CartRepository) Resume(cartID int) error {
+	res, err := r.db.Exec("UPDATE carts SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3",
+		models.CartStatusOpen, cartID, models.CartStatusHeld)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM carts WHERE id = $1)", cartID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return models.ErrCartNotFound
+	}
+	return models.ErrCartNotHeld
+}
+
+// Checkout mengubah keranjang open menjadi transaksi memakai logika checkout yang sama dengan
+// POST /checkout, dalam satu database transaction sehingga keranjang tidak bisa di-checkout dua kali.
+func (r *CartRepository) Checkout(cartID int, req models.CartCheckoutRequest, actor string) (*models.Transaction, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenCart(tx, cartID); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query("SELECT product_id, COALESCE(variant_id, 0), quantity FROM cart_items WHERE cart_id = $1 ORDER BY product_id, variant_id", cartID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.CheckoutItem, 0)
+	for rows.Next() {
+		var item models.CheckoutItem
+		if err := rows.Scan(&item.ProductID, &item.VariantID, &item.Quantity); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, models.ErrCartEmpty
+	}
+
+	transaction, err := createTransaction(tx, models.CheckoutRequest{
+		Items:        items,
+		VoucherCodes: req.VoucherCodes,
+		Payments:     req.Payments,
+	}, true, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("UPDATE carts SET status = $1, transaction_id = $2, updated_at = NOW() WHERE id = $3",
+		models.CartStatusCheckedOut, transaction.ID, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// withOpenCart menjalankan fn dalam transaction setelah keranjang di-lock dan dipastikan open,
+// lalu memperbarui updated_at sehingga masa kedaluwarsa keranjang diperpanjang.
+func (r *CartRepository) withOpenCart(cartID int, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenCart(tx, cartID); err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE carts SET updated_at = NOW() WHERE id = $1", cartID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func lockOpenCart(tx *sql.Tx, cartID int) error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM carts WHERE id = $1 FOR UPDATE", cartID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return models.ErrCartNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.CartStatusOpen {
+		return models.ErrCartNotOpen
+	}
+	return nil
+}
+
+// checkCartStock memastikan produk (dan variannya) ada dan stok saat ini cukup untuk quantity.
+// Produk bervarian wajib menyebut varian. Stok tidak di-reserve; checkout tetap memvalidasi ulang stok.
+func checkCartStock(tx *sql.Tx, productID, variantID, quantity int) error {
+	var name string
+	var stock int
+	var hasVariants bool
+	err := tx.QueryRow(`
+		SELECT name, stock, EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id)
+		FROM products p WHERE id = $1 AND deleted_at IS NULL
+	`, productID).Scan(&name, &stock, &hasVariants)
+	if err == sql.ErrNoRows {
+		return models.ErrProductNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var variantName string
+	if variantID == 0 {
+		if hasVariants {
+			return fmt.Errorf("%w: product id %d", models.ErrVariantRequired, productID)
+		}
+	} else {
+		err := tx.QueryRow(`
+			SELECT v.stock, `+variantNameSQL+`
+			FROM product_variants v WHERE v.id = $1 AND v.product_id = $2
+		`, variantID, productID).Scan(&stock, &variantName)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: variant id %d", models.ErrVariantNotFound, variantID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if quantity > stock {
+		return &models.ErrInsufficientStock{Items: []models.InsufficientStockItem{{
+			ProductID:   productID,
+			ProductName: name,
+			VariantID:   variantRef(variantID),
+			VariantName: variantName,
+			Requested:   quantity,
+			Available:   stock,
+		}}}
+	}
+	return nil
+}
+
+func requireCartItem(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return models.ErrCartItemNotFound
+	}
+	return nil
+}
@@ -2,16 +2,18 @@ package repository
 
 import (
 	"database/sql"
-	"errors"
+	"fmt"
 	model "simple-crud/models"
 )
 
 type CategoriesRepository interface {
-	GetAll() ([]model.Category, error)
-	GetByID(id int) (*model.Category, error)
+	GetAll(includeDeleted bool) ([]model.Category, error)
+	GetByID(id int, includeDeleted bool) (*model.Category, error)
 	Create(c model.Category) (*model.Category, error)
-	Update(id int, c model.Category) error
-	Delete(id int) error
+	Update(id int, c model.Category, ifMatch model.IfMatch) error
+	Delete(id int, opts model.CategoryDeleteOptions, ifMatch model.IfMatch) (*model.CategoryDeleteResult, error)
+	Restore(id int) error
+	Import(rows []model.CategoryImportRow, report *model.ImportReport, commit bool) error
 }
 
 type CategoryRepository struct {
@@ -24,9 +26,11 @@ func NewCategoryRepository(db *sql.DB) *CategoryRepository {
 	}
 }
 
-func (r *CategoryRepository) GetAll() ([]model.Category, error) {
-	query := "SELECT id, name, description FROM categories"
-	rows, err := r.db.Query(query)
+// GetAll mengembalikan seluruh kategori urut nama, sehingga bisa langsung disusun menjadi pohon.
+// Kategori yang sudah dihapus hanya ikut jika includeDeleted.
+func (r *CategoryRepository) GetAll(includeDeleted bool) ([]model.Category, error) {
+	query := "SELECT id, name, description, tax_rate_id, parent_id, deleted_at, version FROM categories WHERE $1 OR deleted_at IS NULL ORDER BY name, id"
+	rows, err := r.db.Query(query, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +39,7 @@ func (r *CategoryRepository) GetAll() ([]model.Category, error) {
 	categories := make([]model.Category, 0)
 	for rows.Next() {
 		var c model.Category
-		if err := rows.Scan(&c.ID, &c.Name, &c.Description); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.TaxRateID, &c.ParentID, &c.DeletedAt, &c.Version); err != nil {
 			return nil, err
 		}
 		categories = append(categories, c)
@@ -48,13 +52,13 @@ func (r *CategoryRepository) GetAll() ([]model.Category, error) {
 	return categories, nil
 }
 
-func (r *CategoryRepository) GetByID(id int) (*model.Category, error) {
-	query := "SELECT id, name, description FROM categories WHERE id = $1"
+func (r *CategoryRepository) GetByID(id int, includeDeleted bool) (*model.Category, error) {
+	query := "SELECT id, name, description, tax_rate_id, parent_id, deleted_at, version FROM categories WHERE id = $1 AND ($2 OR deleted_at IS NULL)"
 	var c model.Category
-	err := r.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description)
+	err := r.db.QueryRow(query, id, includeDeleted).Scan(&c.ID, &c.Name, &c.Description, &c.TaxRateID, &c.ParentID, &c.DeletedAt, &c.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("Kategori tidak ditemukan")
+			return nil, model.ErrCategoryNotFound
 		}
 		return nil, err
 	}
@@ -62,20 +66,55 @@ func (r *CategoryRepository) GetByID(id int) (*model.Category, error) {
 }
 
 func (r *CategoryRepository) Create(c model.Category) (*model.Category, error) {
-	query := "INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id"
-	var id int
-	err := r.db.QueryRow(query, c.Name, c.Description).Scan(&id)
+	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
+
+	if err := validateCategoryParent(tx, 0, c.ParentID); err != nil {
+		return nil, err
+	}
+
+	query := "INSERT INTO categories (name, description, tax_rate_id, parent_id) VALUES ($1, $2, $3, $4) RETURNING id, version"
+	err = tx.QueryRow(query, c.Name, c.Description, c.TaxRateID, c.ParentID).Scan(&c.ID, &c.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
-	c.ID = id
 	return &c, nil
 }
 
-func (r *CategoryRepository) Update(id int, c model.Category) error {
-	query := "UPDATE categories SET name = $1, description = $2 WHERE id = $3"
-	result, err := r.db.Exec(query, c.Name, c.Description, id)
+// Update menimpa data kategori jika versinya cocok dengan ifMatch
+func (r *CategoryRepository) Update(id int, c model.Category, ifMatch model.IfMatch) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := validateCategoryParent(tx, id, c.ParentID); err != nil {
+		return err
+	}
+
+	var version int
+	err = tx.QueryRow("SELECT version FROM categories WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&version)
+	if err == sql.ErrNoRows {
+		return model.ErrCategoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := ifMatch.Check(version); err != nil {
+		return err
+	}
+
+	query := "UPDATE categories SET name = $1, description = $2, tax_rate_id = $3, parent_id = $4, version = version + 1 WHERE id = $5 AND deleted_at IS NULL"
+	result, err := tx.Exec(query, c.Name, c.Description, c.TaxRateID, c.ParentID, id)
 
 	if err != nil {
 		return err
@@ -87,14 +126,164 @@ func (r *CategoryRepository) Update(id int, c model.Category) error {
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("Kategori tidak ditemukan")
+		return model.ErrCategoryNotFound
+	}
+
+	return tx.Commit()
+}
+
+// Delete menghapus kategori (soft delete). Sub-kategori aktif membuat hapus ditolak, kecuali opts.Children = reparent:
+// sub-kategori dipindahkan ke parent kategori yang dihapus (atau menjadi level teratas). Produk aktif yang masih
+// memakai kategori membuat hapus ditolak dengan jumlahnya, kecuali opts.ReassignTo diisi: produk dipindah
+// ke kategori tersebut di transaksi yang sama. Sub-kategori dan produk yang sudah dihapus ikut dipindahkan
+// sehingga tetap bisa di-restore ke kategori aktif. Hapus ditolak jika versi kategori tidak cocok dengan ifMatch.
+func (r *CategoryRepository) Delete(id int, opts model.CategoryDeleteOptions, ifMatch model.IfMatch) (*model.CategoryDeleteResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock baris kategori juga menahan insert/update produk yang merujuknya (FK) sampai hapus selesai
+	var parentID *int
+	var version int
+	err = tx.QueryRow("SELECT parent_id, version FROM categories WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&parentID, &version)
+	if err == sql.ErrNoRows {
+		return nil, model.ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := ifMatch.Check(version); err != nil {
+		return nil, err
+	}
+
+	var childCount int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM categories WHERE parent_id = $1 AND deleted_at IS NULL", id).Scan(&childCount); err != nil {
+		return nil, err
+	}
+	if childCount > 0 && opts.Children != model.CategoryChildrenReparent {
+		return nil, fmt.Errorf("%w: %d sub-kategori, gunakan children=reparent untuk memindahkannya ke parent", model.ErrCategoryHasChildren, childCount)
+	}
+	if opts.Children == model.CategoryChildrenReparent {
+		if _, err := tx.Exec("UPDATE categories SET parent_id = $2, version = version + 1 WHERE parent_id = $1", id, parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &model.CategoryDeleteResult{ID: id}
+	if opts.ReassignTo != nil {
+		// Kategori tujuan di-lock FOR SHARE agar tidak terhapus bersamaan sebelum produk selesai dipindah
+		var deleted bool
+		err := tx.QueryRow("SELECT deleted_at IS NOT NULL FROM categories WHERE id = $1 FOR SHARE", *opts.ReassignTo).Scan(&deleted)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		if err == sql.ErrNoRows || deleted {
+			return nil, fmt.Errorf("%w: kategori id %d tidak ditemukan", model.ErrInvalidCategoryReassign, *opts.ReassignTo)
+		}
+
+		res, err := tx.Exec("UPDATE products SET category_id = $2, version = version + 1 WHERE category_id = $1", id, *opts.ReassignTo)
+		if err != nil {
+			return nil, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		result.ReassignedProducts = int(n)
+	} else {
+		var usage model.CategoryUsage
+		if err := tx.QueryRow("SELECT COUNT(*) FROM products WHERE category_id = $1 AND deleted_at IS NULL", id).Scan(&usage.ProductCount); err != nil {
+			return nil, err
+		}
+		if usage.ProductCount > 0 {
+			return nil, &model.ErrCategoryInUse{Usage: usage}
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE categories SET deleted_at = NOW(), version = version + 1 WHERE id = $1", id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Restore mengembalikan kategori yang sudah dihapus. Parent-nya harus masih aktif (restore parent dulu).
+// Produk dan sub-kategori yang ikut terhapus di-restore terpisah.
+func (r *CategoryRepository) Restore(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parentID *int
+	var deleted bool
+	err = tx.QueryRow("SELECT parent_id, deleted_at IS NOT NULL FROM categories WHERE id = $1 FOR UPDATE", id).Scan(&parentID, &deleted)
+	if err == sql.ErrNoRows {
+		return model.ErrCategoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return model.ErrCategoryNotDeleted
+	}
+
+	if parentID != nil {
+		if err := requireActiveCategory(tx, *parentID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE categories SET deleted_at = NULL, version = version + 1 WHERE id = $1", id); err != nil {
+		return err
 	}
+	return tx.Commit()
+}
 
+// validateCategoryParent memastikan parent ada, belum dihapus, dan bukan kategori itu sendiri atau turunannya.
+// Tabel di-lock agar dua perubahan parent yang berjalan bersamaan tidak bisa membentuk siklus.
+func validateCategoryParent(tx *sql.Tx, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return fmt.Errorf("%w: kategori tidak bisa menjadi parent dirinya sendiri", model.ErrInvalidCategoryParent)
+	}
+
+	if _, err := tx.Exec("LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return err
+	}
+
+	var exists, descendant bool
+	err := tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1 AND deleted_at IS NULL),
+			$1 IN (`+categorySubtreeQuery("$2")+`)
+	`, *parentID, id).Scan(&exists, &descendant)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: parent id %d tidak ditemukan", model.ErrInvalidCategoryParent, *parentID)
+	}
+	if descendant {
+		return fmt.Errorf("%w: parent id %d adalah sub-kategori dari kategori ini", model.ErrInvalidCategoryParent, *parentID)
+	}
 	return nil
 }
 
-func (r *CategoryRepository) Delete(id int) error {
-	query := "DELETE FROM categories WHERE id = $1"
-	_, err := r.db.Exec(query, id)
-	return err
+// categorySubtreeQuery mengembalikan subquery id kategori beserta seluruh turunannya.
+// placeholder adalah parameter berisi id kategori akar, mis. "$3".
+func categorySubtreeQuery(placeholder string) string {
+	return `WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = ` + placeholder + `
+			UNION ALL
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree`
 }
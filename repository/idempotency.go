@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+	"simple-crud/models"
+	"time"
+)
+
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve mencoba mengklaim key untuk request baru. Jika key berhasil diklaim, nilai kembalian
+// kedua bernilai true. Jika key sudah ada (dan belum kedaluwarsa), record yang tersimpan dikembalikan.
+func (r *IdempotencyRepository) Reserve(key, requestHash string, ttl time.Duration) (*models.IdempotencyKey, bool, error) {
+	// Key yang sudah kedaluwarsa boleh dipakai ulang
+	_, err := r.db.Exec("DELETE FROM idempotency_keys WHERE key = $1 AND expires_at <= NOW()", key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	result, err := r.db.Exec(`
+		INSERT INTO idempotency_keys (key, request_hash, expires_at)
+		VALUES ($1, $2, NOW() + make_interval(secs => $3))
+		ON CONFLICT (key) DO NOTHING
+	`, key, requestHash, ttl.Seconds())
+	if err != nil {
+		return nil, false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if rowsAffected == 1 {
+		return nil, true, nil
+	}
+
+	var k models.IdempotencyKey
+	var transactionID sql.NullInt64
+	err = r.db.QueryRow(`
+		SELECT key, request_hash, transaction_id, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE key = $1
+	`, key).Scan(&k.Key, &k.RequestHash, &transactionID, &k.ResponseBody, &k.CreatedAt, &k.ExpiresAt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if transactionID.Valid {
+		id := int(transactionID.Int64)
+		k.TransactionID = &id
+	}
+
+	return &k, false, nil
+}
+
+// Complete menyimpan transaksi dan response hasil request agar bisa di-replay
+func (r *IdempotencyRepository) Complete(key string, transactionID int, responseBody []byte) error {
+	_, err := r.db.Exec(`
+		UPDATE idempotency_keys
+		SET transaction_id = $2, response_body = $3
+		WHERE key = $1
+	`, key, transactionID, responseBody)
+	return err
+}
+
+// SetTransaction menyimpan transaction_id saja sebagai cadangan jika Complete gagal,
+// sehingga replay bisa membangun ulang response dari transaksinya
+func (r *IdempotencyRepository) SetTransaction(key string, transactionID int) error {
+	_, err := r.db.Exec("UPDATE idempotency_keys SET transaction_id = $2 WHERE key = $1", key, transactionID)
+	return err
+}
+
+// Release menghapus key yang belum selesai (mis. checkout gagal) agar client bisa mencoba lagi.
+// Key yang sudah punya transaksi tidak pernah dilepas.
+func (r *IdempotencyRepository) Release(key string) error {
+	_, err := r.db.Exec("DELETE FROM idempotency_keys WHERE key = $1 AND response_body IS NULL AND transaction_id IS NULL", key)
+	return err
+}
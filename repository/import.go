@@ -0,0 +1,462 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	model "simple-crud/models"
+)
+
+// importLookup berisi data referensi yang dibutuhkan untuk memvalidasi baris import tanpa query per baris
+type importLookup struct {
+	categoryIDs   map[int]bool
+	categoryNames map[string][]int // nama kategori (lowercase) -> id; lebih dari satu berarti ambigu
+	taxRateIDs    map[int]bool
+}
+
+func loadImportLookup(tx *sql.Tx) (*importLookup, error) {
+	l := &importLookup{
+		categoryIDs:   make(map[int]bool),
+		categoryNames: make(map[string][]int),
+		taxRateIDs:    make(map[int]bool),
+	}
+
+	// Kategori yang sudah dihapus diperlakukan seperti tidak ada
+	rows, err := tx.Query("SELECT id, name FROM categories WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		l.categoryIDs[id] = true
+		key := strings.ToLower(strings.TrimSpace(name))
+		l.categoryNames[key] = append(l.categoryNames[key], id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query("SELECT id FROM tax_rates")
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		l.taxRateIDs[id] = true
+	}
+	rows.Close()
+	return l, rows.Err()
+}
+
+// resolveCategory mencari id kategori dari category_id atau nama kategori; 0 berarti tidak diisi
+func (l *importLookup) resolveCategory(id int, name string) (int, string) {
+	if id > 0 {
+		if !l.categoryIDs[id] {
+			return 0, fmt.Sprintf("kategori id %d tidak ditemukan", id)
+		}
+		if name != "" && !containsInt(l.categoryNames[strings.ToLower(name)], id) {
+			return 0, fmt.Sprintf("category_id %d tidak cocok dengan kategori %q", id, name)
+		}
+		return id, ""
+	}
+	if name == "" {
+		return 0, ""
+	}
+	switch ids := l.categoryNames[strings.ToLower(name)]; len(ids) {
+	case 0:
+		return 0, fmt.Sprintf("kategori %q tidak ditemukan", name)
+	case 1:
+		return ids[0], ""
+	default:
+		return 0, fmt.Sprintf("nama kategori %q dipakai %d kategori, gunakan category_id", name, len(ids))
+	}
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// productImportPlan adalah baris import yang sudah divalidasi terhadap database
+type productImportPlan struct {
+	row        model.ProductImportRow
+	productID  int // 0 berarti produk baru
+	categoryID *int
+}
+
+// Import memvalidasi seluruh baris terhadap database (kategori, tarif pajak, SKU dan barcode produk lain) lalu,
+// jika commit dan tidak ada error, membuat atau memperbarui produk berdasarkan SKU dalam satu database transaction.
+// Perubahan stok dicatat di ledger stok seperti create/update produk biasa.
+func (r *ProductRepository) Import(rows []model.ProductImportRow, report *model.ImportReport, commit bool, actor string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	lookup, err := loadImportLookup(tx)
+	if err != nil {
+		return err
+	}
+
+	// SKU produk yang sudah dihapus tetap dipesan sampai purge, sehingga import tidak bisa membuat produk baru dengannya
+	productIDs := make(map[string]int)
+	deletedProducts := make(map[int]bool)
+	skuRows, err := tx.Query("SELECT id, sku, deleted_at IS NOT NULL FROM products WHERE sku IS NOT NULL")
+	if err != nil {
+		return err
+	}
+	for skuRows.Next() {
+		var id int
+		var sku string
+		var deleted bool
+		if err := skuRows.Scan(&id, &sku, &deleted); err != nil {
+			skuRows.Close()
+			return err
+		}
+		productIDs[sku] = id
+		deletedProducts[id] = deleted
+	}
+	skuRows.Close()
+	if err := skuRows.Err(); err != nil {
+		return err
+	}
+
+	barcodeOwners := make(map[string]int)
+	barcodeRows, err := tx.Query("SELECT barcode, product_id FROM product_barcodes")
+	if err != nil {
+		return err
+	}
+	for barcodeRows.Next() {
+		var barcode string
+		var id int
+		if err := barcodeRows.Scan(&barcode, &id); err != nil {
+			barcodeRows.Close()
+			return err
+		}
+		barcodeOwners[barcode] = id
+	}
+	barcodeRows.Close()
+	if err := barcodeRows.Err(); err != nil {
+		return err
+	}
+
+	// SKU varian dan produk yang stoknya diatur per varian
+	variantSKUs := make(map[string]bool)
+	variantProducts := make(map[int]bool)
+	variantRows, err := tx.Query("SELECT product_id, COALESCE(sku, '') FROM product_variants")
+	if err != nil {
+		return err
+	}
+	for variantRows.Next() {
+		var id int
+		var sku string
+		if err := variantRows.Scan(&id, &sku); err != nil {
+			variantRows.Close()
+			return err
+		}
+		variantProducts[id] = true
+		if sku != "" {
+			variantSKUs[sku] = true
+		}
+	}
+	variantRows.Close()
+	if err := variantRows.Err(); err != nil {
+		return err
+	}
+
+	plans := make([]productImportPlan, 0, len(rows))
+	for _, row := range rows {
+		errorsBefore := len(report.Errors)
+		plan := productImportPlan{row: row, productID: productIDs[row.SKU]}
+
+		if variantSKUs[row.SKU] {
+			report.AddError(row.Row, "sku", fmt.Sprintf("sku %s sudah dipakai varian produk", row.SKU))
+		}
+		if deletedProducts[plan.productID] {
+			report.AddError(row.Row, "sku", fmt.Sprintf("sku %s milik produk id %d yang sudah dihapus, restore produk dulu", row.SKU, plan.productID))
+		}
+		if row.Stock != nil && variantProducts[plan.productID] {
+			report.AddError(row.Row, "stock", model.ErrProductHasVariants.Error())
+		}
+
+		categoryID, msg := lookup.resolveCategory(row.CategoryID, row.CategoryName)
+		if msg != "" {
+			report.AddError(row.Row, "category", msg)
+		} else if categoryID > 0 {
+			plan.categoryID = &categoryID
+		}
+		if row.TaxRateID != nil && !lookup.taxRateIDs[*row.TaxRateID] {
+			report.AddError(row.Row, "tax_rate_id", fmt.Sprintf("tarif pajak id %d tidak ditemukan", *row.TaxRateID))
+		}
+		for _, code := range row.Barcodes {
+			if owner, ok := barcodeOwners[code]; ok && owner != plan.productID {
+				report.AddError(row.Row, "barcodes", fmt.Sprintf("barcode %s sudah terdaftar di produk id %d", code, owner))
+			}
+		}
+
+		if plan.productID == 0 {
+			if row.Name == nil {
+				report.AddError(row.Row, "name", "name wajib diisi untuk produk baru")
+			}
+			if row.Price == nil {
+				report.AddError(row.Row, "price", "price wajib diisi untuk produk baru")
+			}
+			if plan.categoryID == nil && msg == "" {
+				report.AddError(row.Row, "category", "category_id atau category wajib diisi untuk produk baru")
+			}
+		}
+
+		if len(report.Errors) > errorsBefore {
+			continue
+		}
+		if plan.productID == 0 {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+		plans = append(plans, plan)
+	}
+
+	if !commit || len(report.Errors) > 0 {
+		return nil
+	}
+
+	// Update dikerjakan berurutan berdasarkan id produk agar urutan lock sama dengan checkout
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].productID < plans[j].productID
+	})
+	for _, plan := range plans {
+		if plan.productID == 0 {
+			err = createImportedProduct(tx, plan, actor)
+		} else {
+			err = updateImportedProduct(tx, plan, actor)
+		}
+		if err != nil {
+			return fmt.Errorf("baris %d: %w", plan.row.Row, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	report.Committed = true
+	return nil
+}
+
+func createImportedProduct(tx *sql.Tx, plan productImportPlan, actor string) error {
+	row := plan.row
+	product := model.Product{
+		SKU:       row.SKU,
+		Name:      *row.Name,
+		Price:     *row.Price,
+		TaxRateID: row.TaxRateID,
+		Barcodes:  row.Barcodes,
+	}
+	product.CategoryID = *plan.categoryID
+	if row.Stock != nil {
+		product.Stock = *row.Stock
+	}
+	if row.CostPrice != nil {
+		product.CostPrice = *row.CostPrice
+	}
+	if row.MinStock != nil {
+		product.MinStock = *row.MinStock
+	}
+	if row.ReorderQty != nil {
+		product.ReorderQty = *row.ReorderQty
+	}
+
+	err := tx.QueryRow(`
+		INSERT INTO products (category_id, name, price, stock, cost_price, min_stock, reorder_qty, tax_rate_id, sku)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		product.CategoryID, product.Name, product.Price, product.Stock, product.CostPrice,
+		product.MinStock, product.ReorderQty, product.TaxRateID, product.SKU).Scan(&product.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := saveProductBarcodes(tx, product.ID, product.Barcodes); err != nil {
+		return err
+	}
+
+	return recordStockMovement(tx, model.StockMovement{
+		ProductID:    product.ID,
+		Reason:       model.StockReasonInitial,
+		Quantity:     product.Stock,
+		BalanceAfter: product.Stock,
+		Actor:        actor,
+		Note:         "import",
+	})
+}
+
+// updateImportedProduct hanya mengubah kolom yang diisi di file import
+func updateImportedProduct(tx *sql.Tx, plan productImportPlan, actor string) error {
+	row := plan.row
+
+	var oldStock int
+	if err := tx.QueryRow("SELECT stock FROM products WHERE id = $1 FOR UPDATE", plan.productID).Scan(&oldStock); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`
+		UPDATE products
+		SET name = COALESCE($2, name), category_id = COALESCE($3, category_id), price = COALESCE($4, price),
+			stock = COALESCE($5, stock), cost_price = COALESCE($6, cost_price), min_stock = COALESCE($7, min_stock),
+			reorder_qty = COALESCE($8, reorder_qty), tax_rate_id = COALESCE($9, tax_rate_id), version = version + 1
+		WHERE id = $1`,
+		plan.productID, row.Name, plan.categoryID, row.Price, row.Stock, row.CostPrice, row.MinStock, row.ReorderQty, row.TaxRateID)
+	if err != nil {
+		return err
+	}
+
+	if row.Barcodes != nil {
+		if err := saveProductBarcodes(tx, plan.productID, row.Barcodes); err != nil {
+			return err
+		}
+	}
+
+	if row.Stock == nil {
+		return nil
+	}
+	return recordStockMovement(tx, model.StockMovement{
+		ProductID:    plan.productID,
+		Reason:       model.StockReasonAdjustment,
+		Quantity:     *row.Stock - oldStock,
+		BalanceAfter: *row.Stock,
+		Actor:        actor,
+		Note:         "import",
+	})
+}
+
+// Export memanggil fn untuk setiap produk berurutan berdasarkan id tanpa memuat seluruh katalog ke memori
+func (r *ProductRepository) Export(fn func(model.Product) error) error {
+	rows, err := r.db.Query(`
+		SELECT ` + productColumns + `
+		FROM products p
+		JOIN categories c ON p.category_id = c.id
+		WHERE p.deleted_at IS NULL
+		ORDER BY p.id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product, err := scanProduct(rows.Scan)
+		if err != nil {
+			return err
+		}
+		if err := fn(*product); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Import kategori: baris dengan id memperbarui kategori tersebut, tanpa id dicocokkan dengan nama
+// (case-insensitive), selain itu dibuat sebagai kategori baru. Sama seperti produk, commit bersifat all-or-nothing.
+func (r *CategoryRepository) Import(rows []model.CategoryImportRow, report *model.ImportReport, commit bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	lookup, err := loadImportLookup(tx)
+	if err != nil {
+		return err
+	}
+
+	type categoryPlan struct {
+		row model.CategoryImportRow
+		id  int
+	}
+	plans := make([]categoryPlan, 0, len(rows))
+	newNames := make(map[string]int)
+	for _, row := range rows {
+		errorsBefore := len(report.Errors)
+		plan := categoryPlan{row: row, id: row.ID}
+
+		switch {
+		case row.ID > 0:
+			if !lookup.categoryIDs[row.ID] {
+				report.AddError(row.Row, "id", fmt.Sprintf("kategori id %d tidak ditemukan", row.ID))
+			}
+		default:
+			key := strings.ToLower(row.Name)
+			switch ids := lookup.categoryNames[key]; {
+			case len(ids) == 1:
+				plan.id = ids[0]
+			case len(ids) > 1:
+				report.AddError(row.Row, "name", fmt.Sprintf("nama kategori %q dipakai %d kategori, gunakan id", row.Name, len(ids)))
+			case newNames[key] > 0:
+				report.AddError(row.Row, "name", fmt.Sprintf("kategori %q sudah ada di baris %d", row.Name, newNames[key]))
+			default:
+				newNames[key] = row.Row
+			}
+		}
+		if row.TaxRateID != nil && !lookup.taxRateIDs[*row.TaxRateID] {
+			report.AddError(row.Row, "tax_rate_id", fmt.Sprintf("tarif pajak id %d tidak ditemukan", *row.TaxRateID))
+		}
+
+		if len(report.Errors) > errorsBefore {
+			continue
+		}
+		if plan.id == 0 {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+		plans = append(plans, plan)
+	}
+
+	if !commit || len(report.Errors) > 0 {
+		return nil
+	}
+
+	for _, plan := range plans {
+		row := plan.row
+		if plan.id == 0 {
+			description := ""
+			if row.Description != nil {
+				description = *row.Description
+			}
+			_, err = tx.Exec("INSERT INTO categories (name, description, tax_rate_id) VALUES ($1, $2, $3)",
+				row.Name, description, row.TaxRateID)
+		} else {
+			_, err = tx.Exec(`
+				UPDATE categories
+				SET name = COALESCE(NULLIF($2, ''), name), description = COALESCE($3, description), tax_rate_id = COALESCE($4, tax_rate_id),
+					version = version + 1
+				WHERE id = $1`,
+				plan.id, row.Name, row.Description, row.TaxRateID)
+		}
+		if err != nil {
+			return fmt.Errorf("baris %d: %w", row.Row, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	report.Committed = true
+	return nil
+}
@@ -2,15 +2,22 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
+
 	model "simple-crud/models"
 )
 
 type ProductRepositories interface {
-	GetAll(name string) ([]model.Product, error)
-	GetByID(id int) (*model.Product, error)
-	Create(product *model.Product) (*model.Product, error)
-	Update(product *model.Product) error
-	Delete(id int) error
+	GetAll(filter model.ProductFilter) (*model.ProductPage, error)
+	GetByID(id int, includeDeleted bool) (*model.Product, error)
+	GetByBarcode(barcode string) (*model.Product, error)
+	Create(product *model.Product, actor string) (*model.Product, error)
+	Update(product *model.Product, ifMatch model.IfMatch, actor string) error
+	Delete(id int, ifMatch model.IfMatch) error
+	Restore(id int) error
+	Import(rows []model.ProductImportRow, report *model.ImportReport, commit bool, actor string) error
+	Export(fn func(model.Product) error) error
 }
 
 type ProductRepository struct {
@@ -23,28 +30,103 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 	}
 }
 
-func (r *ProductRepository) GetAll(name string) ([]model.Product, error) {
+// productSortColumns memetakan nilai sort ke kolom dan tipe parameter cursor-nya
+var productSortColumns = map[string][2]string{
+	model.ProductSortID:    {"p.id", "int"},
+	model.ProductSortName:  {"p.name", "text"},
+	model.ProductSortPrice: {"p.price", "numeric"},
+	model.ProductSortStock: {"p.stock", "int"},
+}
+
+// GetAll mengembalikan satu halaman produk yang cocok dengan filter beserta total seluruhnya.
+// Urutan selalu ditambah p.id sebagai tie-breaker sehingga offset maupun cursor stabil.
+func (r *ProductRepository) GetAll(filter model.ProductFilter) (*model.ProductPage, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "p.deleted_at IS NULL")
+	}
+	if filter.Name != "" {
+		addCondition("p.name ILIKE $%d", "%"+filter.Name+"%")
+	}
+	if filter.CategoryID > 0 {
+		// Kategori mencakup seluruh sub-kategorinya
+		addCondition("p.category_id IN ("+categorySubtreeQuery("$%d")+")", filter.CategoryID)
+	}
+	if filter.MinPrice != nil {
+		addCondition("p.price >= $%d", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		addCondition("p.price <= $%d", *filter.MaxPrice)
+	}
+	if filter.InStock != nil {
+		if *filter.InStock {
+			conditions = append(conditions, "p.stock > 0")
+		} else {
+			conditions = append(conditions, "p.stock <= 0")
+		}
+	}
+	if filter.StockMin != nil {
+		addCondition("p.stock >= $%d", *filter.StockMin)
+	}
+	if filter.StockMax != nil {
+		addCondition("p.stock <= $%d", *filter.StockMax)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM products p"+where, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	sortColumn, ok := productSortColumns[filter.Sort]
+	if !ok {
+		sortColumn = productSortColumns[model.ProductSortID]
+	}
+	column, castType := sortColumn[0], sortColumn[1]
+
+	// Halaman sebelumnya diambil dengan urutan terbalik lalu dibalik lagi setelah dibaca
+	desc := filter.Desc
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	if backward {
+		desc = !desc
+	}
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.Cursor != nil {
+		args = append(args, filter.Cursor.Value, filter.Cursor.ID)
+		condition := fmt.Sprintf("(%s, p.id) %s ($%d::%s, $%d)", column, comparison, len(args)-1, castType, len(args))
+		if where == "" {
+			where = " WHERE " + condition
+		} else {
+			where += " AND " + condition
+		}
+	}
+
 	query := `
-	SELECT
-		p.id,
-		p.category_id,
-		c.name AS category_name,
-		p.name,
-		p.price,
-		p.stock
-	FROM products p
-	JOIN categories c
-		ON p.category_id = c.id
-`
-
-	args := []interface{}{}
-	if name != "" {
-		query += " WHERE p.name ILIKE $1"
-		args = append(args, "%"+name+"%")
-	}
-
-	// terminate query after optional WHERE
-	query += ";"
+		SELECT ` + productColumns + `
+		FROM products p
+		JOIN categories c ON p.category_id = c.id` + where +
+		fmt.Sprintf(" ORDER BY %s %s, p.id %s LIMIT $%d", column, direction, direction, len(args)+1)
+	// Ambil satu baris lebih untuk mengetahui apakah masih ada halaman berikutnya
+	args = append(args, filter.Limit+1)
+	if filter.Cursor == nil {
+		query += fmt.Sprintf(" OFFSET $%d", len(args)+1)
+		args = append(args, (filter.Page-1)*filter.Limit)
+	}
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
@@ -52,96 +134,355 @@ func (r *ProductRepository) GetAll(name string) ([]model.Product, error) {
 	}
 	defer rows.Close()
 
-	var products []model.Product
+	products := make([]model.Product, 0)
 	for rows.Next() {
-		var product model.Product
-		if err := rows.Scan(
-			&product.ID,
-			&product.CategoryID,
-			&product.CategoryName,
-			&product.Name,
-			&product.Price,
-			&product.Stock,
-		); err != nil {
+		product, err := scanProduct(rows.Scan)
+		if err != nil {
 			return nil, err
 		}
-		products = append(products, product)
+		products = append(products, *product)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return products, nil
+	hasMore := len(products) > filter.Limit
+	if hasMore {
+		products = products[:filter.Limit]
+	}
+	if backward {
+		for i, j := 0, len(products)-1; i < j; i, j = i+1, j-1 {
+			products[i], products[j] = products[j], products[i]
+		}
+	}
+
+	page := &model.ProductPage{Products: products, Total: total}
+	if len(products) == 0 {
+		return page, nil
+	}
+
+	// Cursor selalu dibuat agar klien bisa beralih dari offset ke keyset di halaman mana pun
+	hasNext, hasPrev := hasMore, filter.Cursor != nil || filter.Page > 1
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+	cursor := func(p model.Product, backward bool) string {
+		return model.ProductCursor{Sort: filter.Sort, Desc: filter.Desc, Value: p.SortValue(filter.Sort), ID: p.ID, Backward: backward}.Encode()
+	}
+	if hasNext {
+		page.NextCursor = cursor(products[len(products)-1], false)
+	}
+	if hasPrev {
+		page.PrevCursor = cursor(products[0], true)
+	}
+
+	return page, nil
 }
 
-func (r *ProductRepository) GetByID(id int) (*model.Product, error) {
-	query := `
-		SELECT
-			p.id,
-			p.category_id,
-			c.name AS category_name,
-			p.name,
-			p.price,
-			p.stock
-		FROM products p
-		JOIN categories c
-			ON p.category_id = c.id
-		WHERE p.id = $1;
-	`
-	row := r.db.QueryRow(query, id)
+// productColumns dipakai bersama scanProduct; barcode digabung dengan koma karena hanya berisi angka
+const productColumns = `p.id, p.category_id, c.name, p.name, p.price, p.stock, p.cost_price, p.min_stock, p.reorder_qty,
+	p.tax_rate_id, COALESCE(p.sku, ''),
+	COALESCE((SELECT string_agg(b.barcode, ',' ORDER BY b.barcode) FROM product_barcodes b WHERE b.product_id = p.id), ''),
+	EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id), p.deleted_at, p.version`
 
+func scanProduct(scan func(dest ...any) error) (*model.Product, error) {
 	var product model.Product
-	if err := row.Scan(
+	var barcodes string
+	if err := scan(
 		&product.ID,
 		&product.CategoryID,
 		&product.CategoryName,
 		&product.Name,
 		&product.Price,
 		&product.Stock,
+		&product.CostPrice,
+		&product.MinStock,
+		&product.ReorderQty,
+		&product.TaxRateID,
+		&product.SKU,
+		&barcodes,
+		&product.HasVariants,
+		&product.DeletedAt,
+		&product.Version,
 	); err != nil {
 		return nil, err
 	}
 
+	product.Barcodes = make([]string, 0)
+	if barcodes != "" {
+		product.Barcodes = strings.Split(barcodes, ",")
+	}
 	return &product, nil
 }
 
-func (r *ProductRepository) Create(product *model.Product) (*model.Product, error) {
+// GetByID mengembalikan produk; produk yang sudah dihapus hanya dikembalikan jika includeDeleted
+func (r *ProductRepository) GetByID(id int, includeDeleted bool) (*model.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products p
+		JOIN categories c
+			ON p.category_id = c.id
+		WHERE p.id = $1 AND ($2 OR p.deleted_at IS NULL);
+	`
+	product, err := scanProduct(r.db.QueryRow(query, id, includeDeleted).Scan)
+	if err == sql.ErrNoRows {
+		return nil, model.ErrProductNotFound
+	}
+	return product, err
+}
+
+// GetByBarcode mencari produk dari hasil scan barcode memakai primary key product_barcodes
+func (r *ProductRepository) GetByBarcode(barcode string) (*model.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM product_barcodes pb
+		JOIN products p ON p.id = pb.product_id
+		JOIN categories c ON p.category_id = c.id
+		WHERE pb.barcode = $1 AND p.deleted_at IS NULL;
+	`
+	product, err := scanProduct(r.db.QueryRow(query, barcode).Scan)
+	if err == sql.ErrNoRows {
+		return nil, model.ErrProductNotFound
+	}
+	return product, err
+}
+
+// Create menyimpan produk baru dan mencatat stok awalnya di ledger stok
+func (r *ProductRepository) Create(product *model.Product, actor string) (*model.Product, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := requireActiveCategory(tx, product.CategoryID); err != nil {
+		return nil, err
+	}
+	if err := requireUniqueSKU(tx, product.SKU, 0, 0); err != nil {
+		return nil, err
+	}
+
 	query := `
-		INSERT INTO products (category_id, name, price, stock)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO products (category_id, name, price, stock, cost_price, min_stock, reorder_qty, tax_rate_id, sku)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''))
 		RETURNING id;
 	`
-	row := r.db.QueryRow(query, product.CategoryID, product.Name, product.Price, product.Stock)
+	row := tx.QueryRow(query, product.CategoryID, product.Name, product.Price, product.Stock, product.CostPrice,
+		product.MinStock, product.ReorderQty, product.TaxRateID, product.SKU)
 	if err := row.Scan(&product.ID); err != nil {
 		return nil, err
 	}
+
+	if err := saveProductBarcodes(tx, product.ID, product.Barcodes); err != nil {
+		return nil, err
+	}
+
+	err = recordStockMovement(tx, model.StockMovement{
+		ProductID:    product.ID,
+		Reason:       model.StockReasonInitial,
+		Quantity:     product.Stock,
+		BalanceAfter: product.Stock,
+		Actor:        actor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return product, nil
 }
 
-func (r *ProductRepository) Update(product *model.Product) error {
+// Update menimpa data produk jika versinya cocok dengan ifMatch; selisih stok lama dan baru dicatat
+// sebagai adjustment di ledger stok
+func (r *ProductRepository) Update(product *model.Product, ifMatch model.IfMatch, actor string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var oldStock, version int
+	err = tx.QueryRow("SELECT stock, version FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", product.ID).Scan(&oldStock, &version)
+	if err == sql.ErrNoRows {
+		return model.ErrProductNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := ifMatch.Check(version); err != nil {
+		return err
+	}
+
+	if err := requireActiveCategory(tx, product.CategoryID); err != nil {
+		return err
+	}
+	if err := requireUniqueSKU(tx, product.SKU, product.ID, 0); err != nil {
+		return err
+	}
+	if product.Stock != oldStock {
+		if err := requireNoVariants(tx, product.ID); err != nil {
+			return err
+		}
+	}
+
+	// cost_price tidak diubah: HPP rata-rata dikelola penerimaan barang PO, sehingga payload tanpa cost_price
+	// tidak mereset HPP menjadi 0
 	query := `
 		UPDATE products
-		SET category_id = $2, name = $3, price = $4, stock = $5
-		WHERE id = $1;
+		SET category_id = $2, name = $3, price = $4, stock = $5, min_stock = $6, reorder_qty = $7, tax_rate_id = $8,
+			sku = NULLIF($9, ''), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL;
 	`
-	_, err := r.db.Exec(query, product.ID, product.CategoryID, product.Name, product.Price, product.Stock)
+	result, err := tx.Exec(query, product.ID, product.CategoryID, product.Name, product.Price, product.Stock,
+		product.MinStock, product.ReorderQty, product.TaxRateID, product.SKU)
 	if err != nil {
 		return err
 	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return model.ErrProductNotFound
+	}
+
+	if err := saveProductBarcodes(tx, product.ID, product.Barcodes); err != nil {
+		return err
+	}
+
+	err = recordStockMovement(tx, model.StockMovement{
+		ProductID:    product.ID,
+		Reason:       model.StockReasonAdjustment,
+		Quantity:     product.Stock - oldStock,
+		BalanceAfter: product.Stock,
+		Actor:        actor,
+		Note:         "update produk",
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete menandai produk sebagai terhapus (soft delete) jika versinya cocok dengan ifMatch. Riwayat transaksi
+// dan ledger stok tetap utuh; produk baru benar-benar dihapus oleh purge job setelah masa retensi.
+func (r *ProductRepository) Delete(id int, ifMatch model.IfMatch) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var version int
+	err = tx.QueryRow("SELECT version FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&version)
+	if err == sql.ErrNoRows {
+		return model.ErrProductNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := ifMatch.Check(version); err != nil {
+		return err
+	}
 
+	if _, err := tx.Exec("UPDATE products SET deleted_at = NOW(), version = version + 1 WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Restore mengembalikan produk yang sudah dihapus. Kategorinya harus masih aktif (restore kategori dulu).
+func (r *ProductRepository) Restore(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var categoryID int
+	var deleted bool
+	err = tx.QueryRow("SELECT category_id, deleted_at IS NOT NULL FROM products WHERE id = $1 FOR UPDATE", id).Scan(&categoryID, &deleted)
+	if err == sql.ErrNoRows {
+		return model.ErrProductNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return model.ErrProductNotDeleted
+	}
+
+	if err := requireActiveCategory(tx, categoryID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE products SET deleted_at = NULL, version = version + 1 WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// requireActiveCategory menolak kategori yang sudah dihapus. Baris kategori di-lock FOR SHARE sehingga
+// soft delete kategori yang berjalan bersamaan menunggu dan ikut menghitung produk ini.
+// Kategori yang tidak ada dibiarkan ditolak oleh foreign key.
+func requireActiveCategory(tx *sql.Tx, categoryID int) error {
+	var deleted bool
+	err := tx.QueryRow("SELECT deleted_at IS NOT NULL FROM categories WHERE id = $1 FOR SHARE", categoryID).Scan(&deleted)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if deleted {
+		return fmt.Errorf("%w: kategori id %d, restore kategori dulu", model.ErrCategoryDeleted, categoryID)
+	}
 	return nil
 }
 
-func (r *ProductRepository) Delete(id int) error {
-	query := `
-		DELETE FROM products
-		WHERE id = $1;
-	`
-	_, err := r.db.Exec(query, id)
+// requireUniqueSKU menolak SKU yang sudah dipakai produk atau varian lain (selain productID/variantID itu sendiri);
+// unique index tetap menjadi pengaman terakhir
+func requireUniqueSKU(tx *sql.Tx, sku string, productID, variantID int) error {
+	if sku == "" {
+		return nil
+	}
+	var exists bool
+	err := tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM products WHERE sku = $1 AND id <> $2)
+			OR EXISTS (SELECT 1 FROM product_variants WHERE sku = $1 AND id <> $3)
+	`, sku, productID, variantID).Scan(&exists)
 	if err != nil {
 		return err
 	}
+	if exists {
+		return fmt.Errorf("%w: %s", model.ErrDuplicateSKU, sku)
+	}
+	return nil
+}
+
+// saveProductBarcodes mengganti seluruh barcode produk dengan daftar baru
+func saveProductBarcodes(tx *sql.Tx, productID int, barcodes []string) error {
+	if _, err := tx.Exec("DELETE FROM product_barcodes WHERE product_id = $1", productID); err != nil {
+		return err
+	}
 
+	for _, barcode := range barcodes {
+		var owner int
+		err := tx.QueryRow("SELECT product_id FROM product_barcodes WHERE barcode = $1", barcode).Scan(&owner)
+		if err == nil {
+			return fmt.Errorf("%w: %s (produk id %d)", model.ErrDuplicateBarcode, barcode, owner)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if _, err := tx.Exec("INSERT INTO product_barcodes (barcode, product_id) VALUES ($1, $2)", barcode, productID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"simple-crud/models"
+)
+
+// queryer dipenuhi oleh *sql.DB maupun *sql.Tx
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+type PromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+const promotionColumns = `id, name, type, code, product_id, category_id, percent_bp, buy_qty, get_qty,
+	amount, min_subtotal, stackable, priority, active, starts_at, ends_at`
+
+func scanPromotion(scan func(dest ...any) error) (*models.Promotion, error) {
+	var p models.Promotion
+	var productID, categoryID sql.NullInt64
+	var startsAt, endsAt sql.NullTime
+	if err := scan(&p.ID, &p.Name, &p.Type, &p.Code, &productID, &categoryID, &p.PercentBP, &p.BuyQty, &p.GetQty,
+		&p.Amount, &p.MinSubtotal, &p.Stackable, &p.Priority, &p.Active, &startsAt, &endsAt); err != nil {
+		return nil, err
+	}
+
+	if productID.Valid {
+		id := int(productID.Int64)
+		p.ProductID = &id
+	}
+	if categoryID.Valid {
+		id := int(categoryID.Int64)
+		p.CategoryID = &id
+	}
+	if startsAt.Valid {
+		p.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		p.EndsAt = &endsAt.Time
+	}
+
+	return &p, nil
+}
+
+func queryPromotions(q queryer, query string, args ...any) ([]models.Promotion, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := make([]models.Promotion, 0)
+	for rows.Next() {
+		p, err := scanPromotion(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+// getActivePromotions mengembalikan promo yang aktif dan berada dalam periode berlakunya saat ini.
+// Target kategori diperluas ke seluruh sub-kategorinya.
+func getActivePromotions(q queryer) ([]models.Promotion, error) {
+	promotions, err := queryPromotions(q, `
+		SELECT `+promotionColumns+`
+		FROM promotions
+		WHERE active
+			AND (starts_at IS NULL OR starts_at <= NOW())
+			AND (ends_at IS NULL OR ends_at > NOW())
+		ORDER BY priority, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range promotions {
+		if promotions[i].CategoryID == nil {
+			continue
+		}
+		promotions[i].CategoryIDs, err = queryCategorySubtree(q, *promotions[i].CategoryID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return promotions, nil
+}
+
+// queryCategorySubtree mengembalikan id kategori beserta seluruh turunannya
+func queryCategorySubtree(q queryer, categoryID int) ([]int, error) {
+	rows, err := q.Query(categorySubtreeQuery("$1"), categoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *PromotionRepository) GetAll() ([]models.Promotion, error) {
+	return queryPromotions(r.db, "SELECT "+promotionColumns+" FROM promotions ORDER BY priority, id")
+}
+
+func (r *PromotionRepository) GetActive() ([]models.Promotion, error) {
+	return getActivePromotions(r.db)
+}
+
+func (r *PromotionRepository) GetByID(id int) (*models.Promotion, error) {
+	row := r.db.QueryRow("SELECT "+promotionColumns+" FROM promotions WHERE id = $1", id)
+	p, err := scanPromotion(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrPromotionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *PromotionRepository) Create(p models.Promotion) (*models.Promotion, error) {
+	query := `
+		INSERT INTO promotions (name, type, code, product_id, category_id, percent_bp, buy_qty, get_qty,
+			amount, min_subtotal, stackable, priority, active, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id
+	`
+	err := r.db.QueryRow(query, p.Name, p.Type, p.Code, p.ProductID, p.CategoryID, p.PercentBP, p.BuyQty, p.GetQty,
+		p.Amount, p.MinSubtotal, p.Stackable, p.Priority, p.Active, p.StartsAt, p.EndsAt).Scan(&p.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PromotionRepository) Update(id int, p models.Promotion) error {
+	query := `
+		UPDATE promotions
+		SET name = $2, type = $3, code = $4, product_id = $5, category_id = $6, percent_bp = $7, buy_qty = $8,
+			get_qty = $9, amount = $10, min_subtotal = $11, stackable = $12, priority = $13, active = $14,
+			starts_at = $15, ends_at = $16
+		WHERE id = $1
+	`
+	result, err := r.db.Exec(query, id, p.Name, p.Type, p.Code, p.ProductID, p.CategoryID, p.PercentBP, p.BuyQty,
+		p.GetQty, p.Amount, p.MinSubtotal, p.Stackable, p.Priority, p.Active, p.StartsAt, p.EndsAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrPromotionNotFound
+	}
+
+	return nil
+}
+
+func (r *PromotionRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM promotions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrPromotionNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,497 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"simple-crud/models"
+)
+
+type PurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+// GetAll mengembalikan PO tanpa baris dan penerimaan, terbaru lebih dulu
+func (r *PurchaseOrderRepository) GetAll(filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, error) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Status != "" {
+		addCondition("po.status = $%d", filter.Status)
+	}
+	if filter.SupplierID > 0 {
+		addCondition("po.supplier_id = $%d", filter.SupplierID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.db.Query(`
+		SELECT po.id, po.supplier_id, s.name, po.status, po.note, po.created_by, po.created_at, po.updated_at, po.sent_at,
+			COALESCE((SELECT SUM(l.quantity_ordered * l.unit_cost) FROM purchase_order_lines l WHERE l.purchase_order_id = po.id), 0)
+		FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id`+where+`
+		ORDER BY po.created_at DESC, po.id DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.PurchaseOrder, 0)
+	for rows.Next() {
+		var po models.PurchaseOrder
+		if err := rows.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.Status, &po.Note, &po.CreatedBy,
+			&po.CreatedAt, &po.UpdatedAt, &po.SentAt, &po.Total); err != nil {
+			return nil, err
+		}
+		orders = append(orders, po)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// GetByID mengembalikan PO beserta baris dan riwayat penerimaannya
+func (r *PurchaseOrderRepository) GetByID(id int) (*models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	err := r.db.QueryRow(`
+		SELECT po.id, po.supplier_id, s.name, po.status, po.note, po.created_by, po.created_at, po.updated_at, po.sent_at
+		FROM purchase_orders po
+		JOIN suppliers s ON s.id = po.supplier_id
+		WHERE po.id = $1
+	`, id).Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.Status, &po.Note, &po.CreatedBy, &po.CreatedAt, &po.UpdatedAt, &po.SentAt)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrPurchaseOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := r.db.Query(`
+		SELECT id, product_id, product_name, variant_id, variant_name, quantity_ordered, quantity_received, unit_cost
+		FROM purchase_order_lines
+		WHERE purchase_order_id = $1
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer lines.Close()
+
+	po.Total = models.NewMoney(0)
+	po.Lines = make([]models.PurchaseOrderLine, 0)
+	for lines.Next() {
+		var l models.PurchaseOrderLine
+		if err := lines.Scan(&l.ID, &l.ProductID, &l.ProductName, &l.VariantID, &l.VariantName, &l.QuantityOrdered, &l.QuantityReceived, &l.UnitCost); err != nil {
+			return nil, err
+		}
+		l.Subtotal = l.UnitCost.Mul(int64(l.QuantityOrdered))
+		po.Total = po.Total.Add(l.Subtotal)
+		po.Lines = append(po.Lines, l)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+
+	receipts, err := r.db.Query(`
+		SELECT gr.id, gr.note, gr.received_by, gr.received_at,
+			gri.purchase_order_line_id, l.product_id, l.product_name, l.variant_id, l.variant_name, gri.quantity, gri.unit_cost
+		FROM goods_receipts gr
+		JOIN goods_receipt_items gri ON gri.goods_receipt_id = gr.id
+		JOIN purchase_order_lines l ON l.id = gri.purchase_order_line_id
+		WHERE gr.purchase_order_id = $1
+		ORDER BY gr.received_at, gr.id, gri.id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer receipts.Close()
+
+	po.Receipts = make([]models.GoodsReceipt, 0)
+	for receipts.Next() {
+		var gr models.GoodsReceipt
+		var item models.GoodsReceiptItem
+		if err := receipts.Scan(&gr.ID, &gr.Note, &gr.ReceivedBy, &gr.ReceivedAt,
+			&item.LineID, &item.ProductID, &item.ProductName, &item.VariantID, &item.VariantName, &item.Quantity, &item.UnitCost); err != nil {
+			return nil, err
+		}
+		if n := len(po.Receipts); n == 0 || po.Receipts[n-1].ID != gr.ID {
+			po.Receipts = append(po.Receipts, gr)
+		}
+		last := &po.Receipts[len(po.Receipts)-1]
+		last.Items = append(last.Items, item)
+	}
+	if err := receipts.Err(); err != nil {
+		return nil, err
+	}
+
+	return &po, nil
+}
+
+// Create membuat PO draft; nama produk disalin ke baris PO
+func (r *PurchaseOrderRepository) Create(req models.PurchaseOrderRequest, actor string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := requireSupplier(tx, req.SupplierID); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = tx.QueryRow("INSERT INTO purchase_orders (supplier_id, status, note, created_by) VALUES ($1, $2, $3, $4) RETURNING id",
+		req.SupplierID, models.POStatusDraft, req.Note, actor).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertPurchaseOrderLines(tx, id, req.Lines); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Update mengganti supplier, catatan dan seluruh baris PO yang masih draft
+func (r *PurchaseOrderRepository) Update(id int, req models.PurchaseOrderRequest) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.POStatusDraft {
+		return fmt.Errorf("%w: hanya PO draft yang bisa diubah", models.ErrPurchaseOrderStatus)
+	}
+
+	if err := requireSupplier(tx, req.SupplierID); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE purchase_orders SET supplier_id = $1, note = $2, updated_at = NOW() WHERE id = $3", req.SupplierID, req.Note, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM purchase_order_lines WHERE purchase_order_id = $1", id); err != nil {
+		return err
+	}
+
+	if err := insertPurchaseOrderLines(tx, id, req.Lines); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Send menandai PO draft sudah dikirim ke supplier
+func (r *PurchaseOrderRepository) Send(id int) error {
+	return r.transition(id, models.POStatusSent, "sent_at = NOW(), ", models.POStatusDraft)
+}
+
+// Cancel membatalkan PO yang belum selesai diterima; barang yang sudah diterima tetap di stok
+func (r *PurchaseOrderRepository) Cancel(id int) error {
+	return r.transition(id, models.POStatusCancelled, "", models.POStatusDraft, models.POStatusSent, models.POStatusPartiallyReceived)
+}
+
+func (r *PurchaseOrderRepository) transition(id int, to, set string, from ...string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, id)
+	if err != nil {
+		return err
+	}
+
+	allowed := false
+	for _, s := range from {
+		if status == s {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: PO berstatus %s tidak bisa menjadi %s", models.ErrPurchaseOrderStatus, status, to)
+	}
+
+	if _, err := tx.Exec("UPDATE purchase_orders SET "+set+"status = $1, updated_at = NOW() WHERE id = $2", to, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Receive mencatat penerimaan barang (boleh sebagian) dalam satu database transaction:
+// stok produk (dan variannya) bertambah, ledger stok mencatat receiving, harga pokok aktual disimpan per baris
+// penerimaan dan harga pokok rata-rata produk diperbarui, lalu status PO menjadi partially_received atau received.
+func (r *PurchaseOrderRepository) Receive(id int, req models.ReceiveRequest, actor string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.POStatusSent && status != models.POStatusPartiallyReceived {
+		return fmt.Errorf("%w: hanya PO sent atau partially_received yang bisa diterima", models.ErrPurchaseOrderStatus)
+	}
+
+	type poLine struct {
+		productID   *int
+		name        string
+		variantID   *int
+		variantName string
+		remaining   int
+		unitCost    models.Money
+	}
+	lines := make(map[int]poLine)
+	rows, err := tx.Query(`
+		SELECT id, product_id, product_name, variant_id, variant_name, quantity_ordered - quantity_received, unit_cost
+		FROM purchase_order_lines
+		WHERE purchase_order_id = $1
+		FOR UPDATE
+	`, id)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var lineID int
+		var l poLine
+		if err := rows.Scan(&lineID, &l.productID, &l.name, &l.variantID, &l.variantName, &l.remaining, &l.unitCost); err != nil {
+			rows.Close()
+			return err
+		}
+		lines[lineID] = l
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, item := range req.Items {
+		l, ok := lines[item.LineID]
+		if !ok {
+			return fmt.Errorf("%w: line %d bukan bagian dari PO ini", models.ErrInvalidPurchaseOrder, item.LineID)
+		}
+		if l.productID == nil {
+			return fmt.Errorf("%w: produk %s pada line %d sudah dihapus", models.ErrProductNotFound, l.name, item.LineID)
+		}
+		if l.variantID == nil && l.variantName != "" {
+			return fmt.Errorf("%w: varian %s %s pada line %d sudah dihapus", models.ErrVariantNotFound, l.name, l.variantName, item.LineID)
+		}
+		if item.Quantity > l.remaining {
+			return fmt.Errorf("%w: %s (line %d) diterima %d, sisa %d", models.ErrOverReceive, l.name, item.LineID, item.Quantity, l.remaining)
+		}
+	}
+
+	var receiptID int
+	err = tx.QueryRow("INSERT INTO goods_receipts (purchase_order_id, note, received_by) VALUES ($1, $2, $3) RETURNING id",
+		id, req.Note, actor).Scan(&receiptID)
+	if err != nil {
+		return err
+	}
+
+	// Update stok berurutan berdasarkan product_id lalu variant_id seperti checkout agar tidak deadlock
+	items := append([]models.ReceiveItemRequest(nil), req.Items...)
+	sort.Slice(items, func(i, j int) bool {
+		a, b := lines[items[i].LineID], lines[items[j].LineID]
+		if *a.productID != *b.productID {
+			return *a.productID < *b.productID
+		}
+		return derefVariant(a.variantID) < derefVariant(b.variantID)
+	})
+
+	note := fmt.Sprintf("PO #%d", id)
+	for _, item := range items {
+		l := lines[item.LineID]
+		unitCost := l.unitCost
+		if item.UnitCost != nil {
+			unitCost = *item.UnitCost
+		}
+
+		// Baca stok dan HPP di bawah lock, hitung HPP rata-rata tertimbang, lalu simpan keduanya dalam satu
+		// UPDATE agar version hanya naik sekali per baris. HPP tetap di level produk walaupun barang diterima per varian.
+		var stock int
+		var cost models.Money
+		err := tx.QueryRow("SELECT stock, cost_price FROM products WHERE id = $1 FOR UPDATE", *l.productID).Scan(&stock, &cost)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: id %d", models.ErrProductNotFound, *l.productID)
+		}
+		if err != nil {
+			return err
+		}
+
+		variantID := derefVariant(l.variantID)
+		if variantID == 0 {
+			// Produk yang diberi varian sesudah PO dibuat harus diterima per varian
+			if err := requireNoVariants(tx, *l.productID); err != nil {
+				return err
+			}
+		} else {
+			res, err := tx.Exec("UPDATE product_variants SET stock = stock + $1 WHERE id = $2", item.Quantity, variantID)
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err != nil {
+				return err
+			} else if n == 0 {
+				return fmt.Errorf("%w: variant id %d", models.ErrVariantNotFound, variantID)
+			}
+		}
+
+		balance := stock + item.Quantity
+		cost = models.WeightedAverageCost(cost, stock, unitCost, item.Quantity)
+		if _, err := tx.Exec("UPDATE products SET stock = $1, cost_price = $2, version = version + 1 WHERE id = $3", balance, cost, *l.productID); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("INSERT INTO goods_receipt_items (goods_receipt_id, purchase_order_line_id, quantity, unit_cost) VALUES ($1, $2, $3, $4)",
+			receiptID, item.LineID, item.Quantity, unitCost)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("UPDATE purchase_order_lines SET quantity_received = quantity_received + $1 WHERE id = $2", item.Quantity, item.LineID); err != nil {
+			return err
+		}
+
+		err = recordStockMovement(tx, models.StockMovement{
+			ProductID:    *l.productID,
+			VariantID:    l.variantID,
+			Reason:       models.StockReasonReceiving,
+			Quantity:     item.Quantity,
+			BalanceAfter: balance,
+			ReferenceID:  &receiptID,
+			Actor:        actor,
+			Note:         note,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var complete bool
+	err = tx.QueryRow("SELECT NOT EXISTS (SELECT 1 FROM purchase_order_lines WHERE purchase_order_id = $1 AND quantity_received < quantity_ordered)", id).
+		Scan(&complete)
+	if err != nil {
+		return err
+	}
+
+	newStatus := models.POStatusPartiallyReceived
+	if complete {
+		newStatus = models.POStatusReceived
+	}
+	if _, err := tx.Exec("UPDATE purchase_orders SET status = $1, updated_at = NOW() WHERE id = $2", newStatus, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func lockPurchaseOrder(tx *sql.Tx, id int) (string, error) {
+	var status string
+	err := tx.QueryRow("SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", models.ErrPurchaseOrderNotFound
+	}
+	return status, err
+}
+
+func requireSupplier(tx *sql.Tx, id int) error {
+	var exists bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM suppliers WHERE id = $1)", id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return models.ErrSupplierNotFound
+	}
+	return nil
+}
+
+func insertPurchaseOrderLines(tx *sql.Tx, id int, lines []models.PurchaseOrderLineRequest) error {
+	for _, line := range lines {
+		var name string
+		err := tx.QueryRow("SELECT name FROM products WHERE id = $1 AND deleted_at IS NULL", line.ProductID).Scan(&name)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: id %d", models.ErrProductNotFound, line.ProductID)
+		}
+		if err != nil {
+			return err
+		}
+		variantName, err := purchaseOrderVariant(tx, line.ProductID, line.VariantID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO purchase_order_lines (purchase_order_id, product_id, product_name, variant_id, variant_name, quantity_ordered, unit_cost)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, id, line.ProductID, name, variantRef(line.VariantID), variantName, line.Quantity, line.UnitCost)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purchaseOrderVariant memastikan baris PO produk bervarian menyebut varian miliknya dan mengembalikan nama
+// varian untuk disalin ke baris PO. Produk tanpa varian mengembalikan nama kosong.
+func purchaseOrderVariant(tx *sql.Tx, productID, variantID int) (string, error) {
+	if variantID == 0 {
+		var hasVariants bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1)", productID).Scan(&hasVariants)
+		if err != nil {
+			return "", err
+		}
+		if hasVariants {
+			return "", fmt.Errorf("%w: product id %d", models.ErrVariantRequired, productID)
+		}
+		return "", nil
+	}
+
+	var name string
+	err := tx.QueryRow("SELECT "+variantNameSQL+" FROM product_variants v WHERE v.id = $1 AND v.product_id = $2", variantID, productID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("%w: variant id %d", models.ErrVariantNotFound, variantID)
+	}
+	return name, err
+}
+
+func derefVariant(id *int) int {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
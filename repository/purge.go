@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+type PurgeRepository struct {
+	db *sql.DB
+}
+
+func NewPurgeRepository(db *sql.DB) *PurgeRepository {
+	return &PurgeRepository{db: db}
+}
+
+// PurgeDeleted menghapus permanen produk dan kategori yang sudah di-soft delete lebih lama dari retention.
+// Ledger stok, hasil stock-take, baris PO dan transaksi menyimpan snapshot nama sehingga histori produk yang
+// di-purge tetap ada dengan product_id NULL. Produk yang masih menjadi target promo tidak di-purge karena promo
+// tanpa produk akan berlaku untuk semua produk; hapus promonya lebih dulu.
+// Produk dihapus lebih dulu; kategori hanya dihapus jika tidak lagi dirujuk produk atau sub-kategori mana pun,
+// dan diulang sampai habis agar rantai parent/sub-kategori yang sama-sama terhapus ikut bersih.
+func (r *PurgeRepository) PurgeDeleted(retention time.Duration) (int64, int64, error) {
+	res, err := r.db.Exec(`
+		DELETE FROM products p
+		WHERE p.deleted_at < NOW() - make_interval(secs => $1)
+			AND NOT EXISTS (SELECT 1 FROM promotions pr WHERE pr.product_id = p.id)
+	`, retention.Seconds())
+	if err != nil {
+		return 0, 0, err
+	}
+	products, err := res.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var categories int64
+	for {
+		res, err := r.db.Exec(`
+			DELETE FROM categories c
+			WHERE c.deleted_at < NOW() - make_interval(secs => $1)
+				AND NOT EXISTS (SELECT 1 FROM products p WHERE p.category_id = c.id)
+				AND NOT EXISTS (SELECT 1 FROM categories ch WHERE ch.parent_id = c.id)
+		`, retention.Seconds())
+		if err != nil {
+			return products, categories, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return products, categories, err
+		}
+		if n == 0 {
+			return products, categories, nil
+		}
+		categories += n
+	}
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"simple-crud/models"
+	"sort"
+)
+
+// refundableLine adalah baris transaction_details beserta quantity/amount yang sudah di-refund.
+// total berisi nominal yang benar-benar dibayar pelanggan (setelah diskon, termasuk pajak).
+type refundableLine struct {
+	detailID       int
+	productID      int
+	variantID      *int
+	variantName    string // terisi jika baris adalah varian, walaupun variannya sudah dihapus
+	quantity       int
+	total          models.Money
+	taxAmount      models.Money
+	refundedQty    int
+	refundedAmount models.Money
+	refundedTax    models.Money
+}
+
+// VoidTransaction membatalkan seluruh sisa item transaksi hari ini dan mengembalikan stoknya
+func (r *TransactionRepository) VoidTransaction(transactionID int, reason, method, actor string) (*models.Refund, error) {
+	return r.createRefund(transactionID, models.RefundTypeVoid, reason, method, nil, actor)
+}
+
+// RefundTransaction me-refund sebagian item transaksi (per baris dan quantity) dan mengembalikan stoknya
+func (r *TransactionRepository) RefundTransaction(transactionID int, reason, method string, items []models.RefundItem, actor string) (*models.Refund, error) {
+	return r.createRefund(transactionID, models.RefundTypeRefund, reason, method, items, actor)
+}
+
+func (r *TransactionRepository) createRefund(transactionID int, refundType, reason, method string, items []models.RefundItem, actor string) (*models.Refund, error) {
+	if method != "" && !models.IsValidPaymentMethod(method) {
+		return nil, fmt.Errorf("%w: method harus salah satu dari cash, qris, debit, credit", models.ErrInvalidPayment)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock baris transaksi agar refund/void untuk transaksi yang sama berjalan berurutan
+	var isToday bool
+	err = tx.QueryRow("SELECT DATE(created_at) = CURRENT_DATE FROM transactions WHERE id = $1 FOR UPDATE", transactionID).Scan(&isToday)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var voided bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM refunds WHERE transaction_id = $1 AND type = $2)", transactionID, models.RefundTypeVoid).Scan(&voided)
+	if err != nil {
+		return nil, err
+	}
+	if voided {
+		return nil, models.ErrTransactionAlreadyVoided
+	}
+
+	if refundType == models.RefundTypeVoid && !isToday {
+		return nil, models.ErrVoidNotAllowed
+	}
+
+	// Tanpa method, uang dikembalikan lewat pembayaran terbesar transaksi (tunai jika transaksi tanpa pembayaran)
+	if method == "" {
+		err = tx.QueryRow(`
+			SELECT COALESCE((SELECT method FROM payments WHERE transaction_id = $1
+				ORDER BY amount - change_amount DESC, id LIMIT 1), $2)
+		`, transactionID, models.PaymentMethodCash).Scan(&method)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lines, err := getRefundableLines(tx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Void = refund seluruh sisa quantity dari setiap baris
+	if refundType == models.RefundTypeVoid {
+		items = make([]models.RefundItem, 0, len(lines))
+		for _, line := range lines {
+			if remaining := line.quantity - line.refundedQty; remaining > 0 {
+				items = append(items, models.RefundItem{TransactionDetailID: line.detailID, Quantity: remaining})
+			}
+		}
+	}
+
+	details, err := buildRefundDetails(lines, items)
+	if err != nil {
+		return nil, err
+	}
+
+	refund := &models.Refund{
+		TransactionID: transactionID,
+		Type:          refundType,
+		Method:        method,
+		Reason:        reason,
+	}
+	refund.TotalAmount = models.NewMoney(0)
+	for _, d := range details {
+		refund.TotalAmount = refund.TotalAmount.Add(d.Amount)
+	}
+
+	err = tx.QueryRow("INSERT INTO refunds (transaction_id, type, total_amount, method, reason) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at",
+		transactionID, refundType, refund.TotalAmount, method, reason).Scan(&refund.ID, &refund.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range details {
+		details[i].RefundID = refund.ID
+		err = tx.QueryRow("INSERT INTO refund_details (refund_id, transaction_detail_id, product_id, quantity, amount, tax_amount) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+			refund.ID, details[i].TransactionDetailID, details[i].ProductID, details[i].Quantity, details[i].Amount, details[i].TaxAmount).Scan(&details[i].ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Varian yang sudah dihapus tidak bisa menerima stok kembali; stok produk induk juga tidak diubah
+		// agar tetap sama dengan jumlah stok variannya
+		line := lines[details[i].TransactionDetailID]
+		if line.variantName != "" && line.variantID == nil {
+			continue
+		}
+
+		// Kembalikan stok produk (produk yang sudah dihapus memiliki product_id 0 sehingga tidak ada yang di-update)
+		var balance int
+		err = tx.QueryRow("UPDATE products SET stock = stock + $1, version = version + 1 WHERE id = $2 RETURNING stock", details[i].Quantity, details[i].ProductID).Scan(&balance)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if line.variantID != nil {
+			if _, err := tx.Exec("UPDATE product_variants SET stock = stock + $1 WHERE id = $2", details[i].Quantity, *line.variantID); err != nil {
+				return nil, err
+			}
+		}
+
+		err = recordStockMovement(tx, models.StockMovement{
+			ProductID:    details[i].ProductID,
+			VariantID:    line.variantID,
+			Reason:       models.StockReasonRefund,
+			Quantity:     details[i].Quantity,
+			BalanceAfter: balance,
+			ReferenceID:  &refund.ID,
+			Actor:        actor,
+			Note:         refundType,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	refund.Details = details
+	return refund, nil
+}
+
+func getRefundableLines(tx *sql.Tx, transactionID int) (map[int]refundableLine, error) {
+	rows, err := tx.Query(`
+		SELECT td.id, COALESCE(td.product_id, 0), td.variant_id, COALESCE(td.variant_name, ''), td.quantity, td.total, td.tax_amount,
+			COALESCE(SUM(rd.quantity), 0), COALESCE(SUM(rd.amount), 0), COALESCE(SUM(rd.tax_amount), 0)
+		FROM transaction_details td
+		LEFT JOIN refund_details rd ON rd.transaction_detail_id = td.id
+		WHERE td.transaction_id = $1
+		GROUP BY td.id
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make(map[int]refundableLine)
+	for rows.Next() {
+		var line refundableLine
+		if err := rows.Scan(&line.detailID, &line.productID, &line.variantID, &line.variantName, &line.quantity, &line.total, &line.taxAmount,
+			&line.refundedQty, &line.refundedAmount, &line.refundedTax); err != nil {
+			return nil, err
+		}
+		lines[line.detailID] = line
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// buildRefundDetails memvalidasi item refund terhadap sisa quantity tiap baris dan menghitung nominalnya.
+// Nominal (dan porsi pajaknya) dihitung proporsional (dibulatkan half away from zero); refund yang
+// menghabiskan sisa baris mendapat seluruh sisa nilai sehingga total refund satu baris selalu sama dengan total-nya.
+func buildRefundDetails(lines map[int]refundableLine, items []models.RefundItem) ([]models.RefundDetail, error) {
+	qtyByDetail := make(map[int]int, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity untuk transaction_detail_id %d harus lebih dari 0", models.ErrInvalidRefundItem, item.TransactionDetailID)
+		}
+		if _, ok := lines[item.TransactionDetailID]; !ok {
+			return nil, fmt.Errorf("%w: transaction_detail_id %d bukan bagian dari transaksi ini", models.ErrInvalidRefundItem, item.TransactionDetailID)
+		}
+		qtyByDetail[item.TransactionDetailID] += item.Quantity
+	}
+
+	if len(qtyByDetail) == 0 {
+		return nil, models.ErrNothingToRefund
+	}
+
+	details := make([]models.RefundDetail, 0, len(qtyByDetail))
+	for detailID, qty := range qtyByDetail {
+		line := lines[detailID]
+		remaining := line.quantity - line.refundedQty
+		if qty > remaining {
+			return nil, fmt.Errorf("%w: transaction_detail_id %d diminta %d, sisa %d", models.ErrRefundExceedsSold, detailID, qty, remaining)
+		}
+
+		amount := line.total.MulRatio(int64(qty), int64(line.quantity))
+		taxAmount := line.taxAmount.MulRatio(int64(qty), int64(line.quantity))
+		if qty == remaining {
+			amount = line.total.Sub(line.refundedAmount)
+			taxAmount = line.taxAmount.Sub(line.refundedTax)
+		}
+
+		details = append(details, models.RefundDetail{
+			TransactionDetailID: detailID,
+			ProductID:           line.productID,
+			Quantity:            qty,
+			Amount:              amount,
+			TaxAmount:           taxAmount,
+		})
+	}
+
+	// Urutkan berdasarkan product_id agar urutan lock baris produk sama dengan checkout
+	sort.Slice(details, func(i, j int) bool {
+		if details[i].ProductID != details[j].ProductID {
+			return details[i].ProductID < details[j].ProductID
+		}
+		return details[i].TransactionDetailID < details[j].TransactionDetailID
+	})
+
+	return details, nil
+}
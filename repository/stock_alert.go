@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+
+	"simple-crud/models"
+)
+
+type StockAlertRepository struct {
+	db *sql.DB
+}
+
+func NewStockAlertRepository(db *sql.DB) *StockAlertRepository {
+	return &StockAlertRepository{db: db}
+}
+
+// recordStockAlert mencatat alert jika stok turun melewati min_stock (sebelumnya di atas, sekarang sampai atau di bawah).
+// Dipanggil di dalam tx checkout setelah stok dikurangi.
+func recordStockAlert(tx *sql.Tx, productID int, productName string, before, after, minStock, transactionID int) error {
+	if minStock <= 0 || after > minStock || before <= minStock {
+		return nil
+	}
+	_, err := tx.Exec(`
+		INSERT INTO stock_alerts (product_id, product_name, stock, min_stock, transaction_id)
+		VALUES ($1, $2, $3, $4, $5)
+	`, productID, productName, after, minStock, transactionID)
+	return err
+}
+
+// GetAfter mengembalikan alert dengan id > afterID, terlama lebih dulu, untuk feed polling/SSE
+func (r *StockAlertRepository) GetAfter(afterID, limit int) ([]models.StockAlert, error) {
+	rows, err := r.db.Query(`
+		SELECT id, product_id, product_name, stock, min_stock, transaction_id, created_at
+		FROM stock_alerts
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := make([]models.StockAlert, 0)
+	for rows.Next() {
+		var a models.StockAlert
+		if err := rows.Scan(&a.ID, &a.ProductID, &a.ProductName, &a.Stock, &a.MinStock, &a.TransactionID, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// LatestID mengembalikan id alert terakhir, 0 jika belum ada
+func (r *StockAlertRepository) LatestID() (int, error) {
+	var id int
+	err := r.db.QueryRow("SELECT COALESCE(MAX(id), 0) FROM stock_alerts").Scan(&id)
+	return id, err
+}
+
+// GetLowStock mengembalikan produk dengan min_stock > 0 dan stok sampai atau di bawah min_stock,
+// beserta quantity terjual bersih selama velocityDays hari terakhir. Produk paling kritis lebih dulu.
+func (r *StockAlertRepository) GetLowStock(velocityDays int) ([]models.LowStockProduct, error) {
+	rows, err := r.db.Query(`
+		SELECT p.id, p.name, c.name, p.stock, p.min_stock, p.reorder_qty,
+			COALESCE((
+				SELECT SUM(td.quantity)
+				FROM transaction_details td
+				JOIN transactions t ON t.id = td.transaction_id
+				WHERE td.product_id = p.id AND t.created_at >= NOW() - make_interval(days => $1)
+			), 0) - COALESCE((
+				SELECT SUM(rd.quantity)
+				FROM refund_details rd
+				JOIN refunds rf ON rf.id = rd.refund_id
+				WHERE rd.product_id = p.id AND rf.created_at >= NOW() - make_interval(days => $1)
+			), 0)
+		FROM products p
+		JOIN categories c ON c.id = p.category_id
+		WHERE p.min_stock > 0 AND p.stock <= p.min_stock AND p.deleted_at IS NULL
+		ORDER BY p.stock - p.min_stock, p.name
+	`, velocityDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]models.LowStockProduct, 0)
+	for rows.Next() {
+		var p models.LowStockProduct
+		if err := rows.Scan(&p.ProductID, &p.ProductName, &p.CategoryName, &p.Stock, &p.MinStock, &p.ReorderQty, &p.QtySold); err != nil {
+			return nil, err
+		}
+		if p.QtySold < 0 {
+			p.QtySold = 0
+		}
+		products = append(products, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"simple-crud/models"
+)
+
+type StockMovementRepository struct {
+	db *sql.DB
+}
+
+func NewStockMovementRepository(db *sql.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: db}
+}
+
+// recordStockMovement menulis satu baris ledger stok. Harus dipanggil di dalam tx yang sama
+// dengan UPDATE products.stock agar ledger dan stok tidak pernah berbeda.
+func recordStockMovement(tx *sql.Tx, m models.StockMovement) error {
+	if m.Quantity == 0 {
+		return nil
+	}
+	return insertStockMovement(tx, &m)
+}
+
+// insertStockMovement menulis baris ledger beserta snapshot nama produk dan mengisi id, product_name serta created_at ke m
+func insertStockMovement(tx *sql.Tx, m *models.StockMovement) error {
+	if m.Actor == "" {
+		m.Actor = models.DefaultActor
+	}
+	return tx.QueryRow(`
+		INSERT INTO stock_movements (product_id, product_name, variant_id, reason, quantity, balance_after, reference_id, actor, note)
+		VALUES ($1, COALESCE((SELECT name FROM products WHERE id = $1), ''), $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, product_name, created_at
+	`, m.ProductID, m.VariantID, m.Reason, m.Quantity, m.BalanceAfter, m.ReferenceID, m.Actor, m.Note).Scan(&m.ID, &m.ProductName, &m.CreatedAt)
+}
+
+// Adjust mengubah stok satu produk (atau satu variannya jika variantID > 0) sebesar delta dan mencatatnya
+// sebagai adjustment di ledger. Stok yang akan menjadi negatif ditolak dengan ErrNegativeStock.
+func (r *StockMovementRepository) Adjust(productID, variantID, delta int, note, actor string) (*models.StockMovement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stock, err := lockStockTarget(tx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if stock+delta < 0 {
+		return nil, fmt.Errorf("%w: stok saat ini %d, perubahan %d", models.ErrNegativeStock, stock, delta)
+	}
+
+	balance, err := applyStockDelta(tx, productID, variantID, delta)
+	if err != nil {
+		return nil, err
+	}
+
+	m := models.StockMovement{
+		ProductID:    productID,
+		VariantID:    variantRef(variantID),
+		Reason:       models.StockReasonAdjustment,
+		Quantity:     delta,
+		BalanceAfter: balance,
+		Actor:        actor,
+		Note:         note,
+	}
+	if err := insertStockMovement(tx, &m); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetByProduct mengembalikan ledger stok satu produk (terbaru lebih dulu) beserta total data sebelum pagination
+func (r *StockMovementRepository) GetByProduct(productID int, filter models.StockMovementFilter) ([]models.StockMovement, int, error) {
+	conditions := []string{"product_id = $1"}
+	args := []interface{}{productID}
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Reason != "" {
+		addCondition("reason = $%d", filter.Reason)
+	}
+	if filter.StartDate != "" {
+		addCondition("DATE(created_at) >= $%d", filter.StartDate)
+	}
+	if filter.EndDate != "" {
+		addCondition("DATE(created_at) <= $%d", filter.EndDate)
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM stock_movements"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, product_id, product_name, variant_id, reason, quantity, balance_after, reference_id, actor, note, created_at
+		FROM stock_movements` + where +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movements := make([]models.StockMovement, 0)
+	for rows.Next() {
+		var m models.StockMovement
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.ProductName, &m.VariantID, &m.Reason, &m.Quantity, &m.BalanceAfter, &m.ReferenceID, &m.Actor, &m.Note, &m.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
+
+// ProductExists memeriksa apakah produk ada
+func (r *StockMovementRepository) ProductExists(productID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", productID).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"simple-crud/models"
+)
+
+type StockTakeRepository struct {
+	db *sql.DB
+}
+
+func NewStockTakeRepository(db *sql.DB) *StockTakeRepository {
+	return &StockTakeRepository{db: db}
+}
+
+func (r *StockTakeRepository) Create(note, actor string) (int, error) {
+	var id int
+	err := r.db.QueryRow("INSERT INTO stock_takes (note, status, created_by) VALUES ($1, $2, $3) RETURNING id",
+		note, models.StockTakeStatusOpen, actor).Scan(&id)
+	return id, err
+}
+
+// GetAll mengembalikan sesi stock-take tanpa item, terbaru lebih dulu. status kosong berarti semua status.
+func (r *StockTakeRepository) GetAll(status string) ([]models.StockTake, error) {
+	where := ""
+	args := []any{}
+	if status != "" {
+		where = " WHERE status = $1"
+		args = append(args, status)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, note, status, created_by, committed_by, created_at, closed_at
+		FROM stock_takes`+where+`
+		ORDER BY created_at DESC, id DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	takes := make([]models.StockTake, 0)
+	for rows.Next() {
+		var st models.StockTake
+		if err := rows.Scan(&st.ID, &st.Note, &st.Status, &st.CreatedBy, &st.CommittedBy, &st.CreatedAt, &st.ClosedAt); err != nil {
+			return nil, err
+		}
+		takes = append(takes, st)
+	}
+
+	return takes, rows.Err()
+}
+
+// GetByID mengembalikan sesi stock-take beserta laporan selisih per produk
+func (r *StockTakeRepository) GetByID(id int) (*models.StockTake, error) {
+	var st models.StockTake
+	err := r.db.QueryRow(`
+		SELECT id, note, status, created_by, committed_by, created_at, closed_at
+		FROM stock_takes WHERE id = $1
+	`, id).Scan(&st.ID, &st.Note, &st.Status, &st.CreatedBy, &st.CommittedBy, &st.CreatedAt, &st.ClosedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrStockTakeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// system_quantity adalah stok sistem saat produk dihitung.
+	// Produk yang sudah di-purge memakai snapshot nama dan tidak lagi punya harga.
+	rows, err := r.db.Query(`
+		SELECT sti.product_id, COALESCE(p.name, sti.product_name), sti.variant_id, sti.variant_name,
+			sti.system_quantity, sti.counted_quantity, COALESCE(v.price, p.price, 0), sti.counted_at
+		FROM stock_take_items sti
+		LEFT JOIN products p ON p.id = sti.product_id
+		LEFT JOIN product_variants v ON v.id = sti.variant_id
+		WHERE sti.stock_take_id = $1
+		ORDER BY COALESCE(p.name, sti.product_name), sti.product_id, sti.variant_name, sti.id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	st.Items = make([]models.StockTakeItem, 0)
+	summary := models.StockTakeSummary{TotalVarianceValue: models.NewMoney(0)}
+	for rows.Next() {
+		var item models.StockTakeItem
+		var price models.Money
+		if err := rows.Scan(&item.ProductID, &item.ProductName, &item.VariantID, &item.VariantName, &item.SystemQuantity,
+			&item.CountedQuantity, &price, &item.CountedAt); err != nil {
+			return nil, err
+		}
+		item.Variance = item.CountedQuantity - item.SystemQuantity
+		item.VarianceValue = price.Mul(int64(item.Variance))
+
+		summary.ItemsCounted++
+		if item.Variance != 0 {
+			summary.ItemsWithVariance++
+		}
+		summary.TotalVariance += item.Variance
+		summary.TotalVarianceValue = summary.TotalVarianceValue.Add(item.VarianceValue)
+
+		st.Items = append(st.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	st.Summary = &summary
+	return &st, nil
+}
+
+// SaveCounts menyimpan hasil hitung beserta stok sistem saat itu; produk atau varian yang sudah dihitung sebelumnya ditimpa.
+// Produk bervarian dihitung per varian dan system_quantity-nya adalah stok varian.
+func (r *StockTakeRepository) SaveCounts(id int, counts []models.StockTakeCount) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, id); err != nil {
+		return err
+	}
+
+	for _, count := range counts {
+		// Snapshot stok sistem saat barang dihitung; penjualan/penerimaan sesudahnya tidak ikut dianggap selisih
+		var productName string
+		var systemQuantity int
+		var hasVariants bool
+		err := tx.QueryRow(`
+			SELECT name, stock, EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id)
+			FROM products p WHERE id = $1 AND deleted_at IS NULL FOR SHARE
+		`, count.ProductID).Scan(&productName, &systemQuantity, &hasVariants)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: id %d", models.ErrProductNotFound, count.ProductID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if count.VariantID == 0 {
+			if hasVariants {
+				return fmt.Errorf("%w: product id %d", models.ErrVariantRequired, count.ProductID)
+			}
+			_, err = tx.Exec(`
+				INSERT INTO stock_take_items (stock_take_id, product_id, product_name, counted_quantity, system_quantity)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (stock_take_id, product_id) WHERE variant_id IS NULL AND variant_name = '' DO UPDATE
+				SET counted_quantity = EXCLUDED.counted_quantity, system_quantity = EXCLUDED.system_quantity,
+					product_name = EXCLUDED.product_name, counted_at = NOW()
+			`, id, count.ProductID, productName, count.CountedQuantity, systemQuantity)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		var variantName string
+		err = tx.QueryRow(`
+			SELECT v.stock, `+variantNameSQL+`
+			FROM product_variants v WHERE v.id = $1 AND v.product_id = $2 FOR SHARE
+		`, count.VariantID, count.ProductID).Scan(&systemQuantity, &variantName)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: variant id %d", models.ErrVariantNotFound, count.VariantID)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO stock_take_items (stock_take_id, product_id, product_name, variant_id, variant_name, counted_quantity, system_quantity)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (stock_take_id, variant_id) WHERE variant_id IS NOT NULL DO UPDATE
+			SET counted_quantity = EXCLUDED.counted_quantity, system_quantity = EXCLUDED.system_quantity,
+				product_name = EXCLUDED.product_name, variant_name = EXCLUDED.variant_name, counted_at = NOW()
+		`, id, count.ProductID, productName, count.VariantID, variantName, count.CountedQuantity, systemQuantity)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Commit memposting selisih setiap produk atau varian yang dihitung sebagai adjustment dalam satu database transaction.
+// Selisih dihitung terhadap stok sistem saat barang dihitung (counted - system_quantity) lalu ditambahkan ke
+// stok saat ini, sehingga penjualan, refund atau penerimaan di antara hitung dan commit tidak terhapus.
+// Selisih varian juga mengubah stok produk induknya; produk yang sudah di-purge dan varian yang sudah dihapus dilewati.
+func (r *StockTakeRepository) Commit(id int, actor string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, id); err != nil {
+		return err
+	}
+
+	// Baris produk yang sudah di-purge atau varian yang sudah dihapus (variant_id NULL dengan snapshot nama)
+	// tidak punya stok lagi
+	rows, err := tx.Query(`
+		SELECT product_id, COALESCE(variant_id, 0), counted_quantity, system_quantity
+		FROM stock_take_items
+		WHERE stock_take_id = $1 AND product_id IS NOT NULL AND (variant_id IS NOT NULL OR variant_name = '')
+		ORDER BY product_id, variant_id NULLS FIRST
+	`, id)
+	if err != nil {
+		return err
+	}
+
+	type countedStock struct {
+		productID int
+		variantID int
+		counted   int
+		system    int
+	}
+	items := make([]countedStock, 0)
+	for rows.Next() {
+		var item countedStock
+		if err := rows.Scan(&item.productID, &item.variantID, &item.counted, &item.system); err != nil {
+			rows.Close()
+			return err
+		}
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		return models.ErrStockTakeEmpty
+	}
+
+	// Lock produk lalu varian berurutan berdasarkan id seperti checkout agar tidak deadlock
+	note := fmt.Sprintf("stock-take #%d", id)
+	for _, item := range items {
+		stock, err := lockStockTakeTarget(tx, item.productID, item.variantID)
+		if err != nil {
+			return err
+		}
+
+		// Stok tidak pernah dibuat negatif walaupun penjualan sesudah hitung melebihi hasil hitung
+		target := stock + item.counted - item.system
+		if target < 0 {
+			target = 0
+		}
+		if target == stock {
+			continue
+		}
+
+		balance, err := applyStockDelta(tx, item.productID, item.variantID, target-stock)
+		if err != nil {
+			return err
+		}
+
+		err = recordStockMovement(tx, models.StockMovement{
+			ProductID:    item.productID,
+			VariantID:    variantRef(item.variantID),
+			Reason:       models.StockReasonAdjustment,
+			Quantity:     target - stock,
+			BalanceAfter: balance,
+			ReferenceID:  &id,
+			Actor:        actor,
+			Note:         note,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stock_takes SET status = $1, committed_by = $2, closed_at = NOW() WHERE id = $3",
+		models.StockTakeStatusCommitted, actor, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Cancel menutup sesi open tanpa mengubah stok
+func (r *StockTakeRepository) Cancel(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, id); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE stock_takes SET status = $1, closed_at = NOW() WHERE id = $2", models.StockTakeStatusCancelled, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockStockTakeTarget mengunci produk (termasuk yang sudah dihapus sesudah dihitung) lalu variannya dan
+// mengembalikan stok yang dihitung. Hitungan tanpa varian ditolak jika produk kini memiliki varian.
+func lockStockTakeTarget(tx *sql.Tx, productID, variantID int) (int, error) {
+	var stock int
+	err := tx.QueryRow("SELECT stock FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: id %d", models.ErrProductNotFound, productID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if variantID == 0 {
+		return stock, requireNoVariants(tx, productID)
+	}
+
+	err = tx.QueryRow("SELECT stock FROM product_variants WHERE id = $1 FOR UPDATE", variantID).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: variant id %d", models.ErrVariantNotFound, variantID)
+	}
+	return stock, err
+}
+
+func lockOpenStockTake(tx *sql.Tx, id int) error {
+	var status string
+	err := tx.QueryRow("SELECT status FROM stock_takes WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return models.ErrStockTakeNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.StockTakeStatusOpen {
+		return models.ErrStockTakeNotOpen
+	}
+	return nil
+}
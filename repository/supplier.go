@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"database/sql"
+
+	"simple-crud/models"
+)
+
+type SupplierRepository struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) *SupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+func (r *SupplierRepository) GetAll() ([]models.Supplier, error) {
+	rows, err := r.db.Query("SELECT id, name, phone, email, address, created_at FROM suppliers ORDER BY name, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := make([]models.Supplier, 0)
+	for rows.Next() {
+		var s models.Supplier
+		if err := rows.Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suppliers, nil
+}
+
+func (r *SupplierRepository) GetByID(id int) (*models.Supplier, error) {
+	var s models.Supplier
+	err := r.db.QueryRow("SELECT id, name, phone, email, address, created_at FROM suppliers WHERE id = $1", id).
+		Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address, &s.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrSupplierNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *SupplierRepository) Create(s models.Supplier) (*models.Supplier, error) {
+	err := r.db.QueryRow("INSERT INTO suppliers (name, phone, email, address) VALUES ($1, $2, $3, $4) RETURNING id, created_at",
+		s.Name, s.Phone, s.Email, s.Address).Scan(&s.ID, &s.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *SupplierRepository) Update(id int, s models.Supplier) error {
+	result, err := r.db.Exec("UPDATE suppliers SET name = $2, phone = $3, email = $4, address = $5 WHERE id = $1",
+		id, s.Name, s.Phone, s.Email, s.Address)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrSupplierNotFound
+	}
+
+	return nil
+}
+
+// Delete menghapus supplier yang belum pernah dipakai purchase order
+func (r *SupplierRepository) Delete(id int) error {
+	var inUse bool
+	if err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM purchase_orders WHERE supplier_id = $1)", id).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return models.ErrSupplierInUse
+	}
+
+	result, err := r.db.Exec("DELETE FROM suppliers WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrSupplierNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+	"simple-crud/models"
+)
+
+type TaxRateRepository struct {
+	db *sql.DB
+}
+
+func NewTaxRateRepository(db *sql.DB) *TaxRateRepository {
+	return &TaxRateRepository{db: db}
+}
+
+func (r *TaxRateRepository) GetAll() ([]models.TaxRate, error) {
+	rows, err := r.db.Query("SELECT id, name, rate_bp, mode, active FROM tax_rates ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make([]models.TaxRate, 0)
+	for rows.Next() {
+		var t models.TaxRate
+		if err := rows.Scan(&t.ID, &t.Name, &t.RateBP, &t.Mode, &t.Active); err != nil {
+			return nil, err
+		}
+		rates = append(rates, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+func (r *TaxRateRepository) GetByID(id int) (*models.TaxRate, error) {
+	var t models.TaxRate
+	err := r.db.QueryRow("SELECT id, name, rate_bp, mode, active FROM tax_rates WHERE id = $1", id).
+		Scan(&t.ID, &t.Name, &t.RateBP, &t.Mode, &t.Active)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTaxRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TaxRateRepository) Create(t models.TaxRate) (*models.TaxRate, error) {
+	err := r.db.QueryRow("INSERT INTO tax_rates (name, rate_bp, mode, active) VALUES ($1, $2, $3, $4) RETURNING id",
+		t.Name, t.RateBP, t.Mode, t.Active).Scan(&t.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TaxRateRepository) Update(id int, t models.TaxRate) error {
+	result, err := r.db.Exec("UPDATE tax_rates SET name = $2, rate_bp = $3, mode = $4, active = $5 WHERE id = $1",
+		id, t.Name, t.RateBP, t.Mode, t.Active)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrTaxRateNotFound
+	}
+
+	return nil
+}
+
+func (r *TaxRateRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM tax_rates WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrTaxRateNotFound
+	}
+
+	return nil
+}
+
+// getActiveTaxRate mengembalikan tarif pajak aktif dengan id tertentu, atau nil jika tidak ada/tidak aktif
+func getActiveTaxRate(tx *sql.Tx, id int) (*models.TaxRate, error) {
+	var t models.TaxRate
+	err := tx.QueryRow("SELECT id, name, rate_bp, mode, active FROM tax_rates WHERE id = $1 AND active", id).
+		Scan(&t.ID, &t.Name, &t.RateBP, &t.Mode, &t.Active)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
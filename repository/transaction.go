@@ -4,6 +4,9 @@ import (
 	"database/sql"
 	"fmt"
 	"simple-crud/models"
+	"sort"
+	"strings"
+	"time"
 )
 
 type TransactionRepository struct {
@@ -14,99 +17,561 @@ func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-func (r *TransactionRepository) CreateTransaction(items []models.CheckoutItem) (*models.Transaction, error) {
-	var (
-		res *models.Transaction
-	)
-
+func (r *TransactionRepository) CreateTransaction(req models.CheckoutRequest, useLock bool, actor string) (*models.Transaction, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	totalAmount := 0
-	details := make([]models.TransactionDetail, 0)
+	res, err := createTransaction(tx, req, useLock, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// createTransaction menjalankan checkout (harga, promo, pajak, stok, pembayaran) di dalam tx milik pemanggil.
+// Pemanggil bertanggung jawab atas commit/rollback, sehingga checkout bisa digabung dengan operasi lain (mis. cart).
+// Setiap pengurangan stok dicatat di ledger stok atas nama actor.
+func createTransaction(tx *sql.Tx, req models.CheckoutRequest, useLock bool, actor string) (*models.Transaction, error) {
+	var err error
+
+	// Barcode dan variant_id diterjemahkan ke product_id dulu supaya scan dan input manual produk yang sama ikut digabung
+	items, err := resolveCheckoutItems(tx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gabungkan produk/varian yang sama dan urutkan agar urutan lock antar transaksi selalu konsisten
+	items = mergeCheckoutItems(items)
+
+	// Tarif pajak produk mengalahkan tarif pajak kategori
+	selectQuery := `
+		SELECT p.id, p.category_id, p.name, p.price, p.cost_price, p.stock, p.min_stock, COALESCE(p.tax_rate_id, c.tax_rate_id),
+			EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id)
+		FROM products p
+		JOIN categories c ON c.id = p.category_id
+		WHERE p.id = $1 AND p.deleted_at IS NULL`
+	// Varian di-lock setelah produk induknya; harga varian kosong berarti memakai harga produk
+	variantQuery := `
+		SELECT v.stock, COALESCE(v.price, p.price), ` + variantNameSQL + `
+		FROM product_variants v
+		JOIN products p ON p.id = v.product_id
+		WHERE v.id = $1 AND v.product_id = $2`
+	if useLock {
+		selectQuery += " FOR UPDATE OF p"
+		variantQuery += " FOR UPDATE OF v"
+	}
+
+	details := make([]models.TransactionDetail, 0, len(items))
+	taxRateIDs := make([]*int, 0, len(items))
+	minStocks := make([]int, 0, len(items))
+	promotionLines := make([]models.PromotionLine, 0, len(items))
+	insufficient := make([]models.InsufficientStockItem, 0)
 
 	for _, item := range items {
 		var productName string
-		var productID, price, stock int
-		err := tx.QueryRow("SELECT id, name, price, stock FROM products WHERE id=$1", item.ProductID).Scan(&productID, &productName, &price, &stock)
+		var productID, categoryID, stock, minStock int
+		var price, cost models.Money
+		var taxRateID *int
+		var hasVariants bool
+		err := tx.QueryRow(selectQuery, item.ProductID).Scan(&productID, &categoryID, &productName, &price, &cost, &stock, &minStock, &taxRateID, &hasVariants)
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+			return nil, fmt.Errorf("%w: product id %d", models.ErrProductNotFound, item.ProductID)
 		}
 
 		if err != nil {
 			return nil, err
 		}
 
-		subtotal := item.Quantity * price
-		totalAmount += subtotal
+		if hasVariants && item.VariantID == 0 {
+			return nil, fmt.Errorf("%w: product id %d", models.ErrVariantRequired, productID)
+		}
+
+		// Untuk varian, stok dan harga yang dipakai adalah milik varian
+		var variantID *int
+		var variantName string
+		if item.VariantID > 0 {
+			err := tx.QueryRow(variantQuery, item.VariantID, productID).Scan(&stock, &price, &variantName)
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("%w: variant id %d", models.ErrVariantNotFound, item.VariantID)
+			}
+			if err != nil {
+				return nil, err
+			}
+			variantID = &item.VariantID
+		}
 
-		// Update hanya stock (tanpa kolom sold)
-		_, err = tx.Exec("UPDATE products SET stock = stock - $1 WHERE id = $2", item.Quantity, productID)
-		if err != nil {
-			return nil, err
+		if item.Quantity > stock {
+			insufficient = append(insufficient, models.InsufficientStockItem{
+				ProductID:   productID,
+				ProductName: productName,
+				VariantID:   variantID,
+				VariantName: variantName,
+				Requested:   item.Quantity,
+				Available:   stock,
+			})
+			continue
 		}
 
 		details = append(details, models.TransactionDetail{
 			ProductID:   productID,
 			ProductName: productName,
+			VariantID:   variantID,
+			VariantName: variantName,
+			UnitPrice:   price,
+			UnitCost:    cost,
 			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
+			Subtotal:    price.Mul(int64(item.Quantity)),
 		})
+		taxRateIDs = append(taxRateIDs, taxRateID)
+		minStocks = append(minStocks, minStock)
+		promotionLines = append(promotionLines, models.PromotionLine{
+			ProductID:  productID,
+			CategoryID: categoryID,
+			UnitPrice:  price,
+			Quantity:   item.Quantity,
+		})
+	}
+
+	if len(insufficient) > 0 {
+		return nil, &models.ErrInsufficientStock{Items: insufficient}
+	}
+
+	// Evaluasi promo aktif pada snapshot harga yang sama dengan yang di-lock
+	promotions, err := getActivePromotions(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	promotionResults, err := models.ApplyPromotions(promotionLines, promotions, req.VoucherCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAmount := models.NewMoney(0)
+	discountAmount := models.NewMoney(0)
+	taxAmount := models.NewMoney(0)
+	taxRates := make(map[int]*models.TaxRate)
+	for i := range details {
+		details[i].DiscountAmount = promotionResults[i].DiscountAmount
+		details[i].Discounts = promotionResults[i].Discounts
+		details[i].Total = details[i].Subtotal.Sub(details[i].DiscountAmount)
+		details[i].TaxAmount = models.NewMoney(0)
+
+		// Pajak dihitung per baris dari nilai setelah diskon
+		if id := taxRateIDs[i]; id != nil {
+			rate, ok := taxRates[*id]
+			if !ok {
+				rate, err = getActiveTaxRate(tx, *id)
+				if err != nil {
+					return nil, err
+				}
+				taxRates[*id] = rate
+			}
+			if rate != nil {
+				details[i].TaxName = rate.Name
+				details[i].TaxRateBP = rate.RateBP
+				details[i].TaxMode = rate.Mode
+				details[i].TaxAmount, details[i].Total = rate.Calculate(details[i].Total)
+			}
+		}
+
+		totalAmount = totalAmount.Add(details[i].Total)
+		discountAmount = discountAmount.Add(details[i].DiscountAmount)
+		taxAmount = taxAmount.Add(details[i].TaxAmount)
+	}
+
+	payments, changeAmount, err := models.SettlePayments(totalAmount, req.Payments)
+	if err != nil {
+		return nil, err
+	}
+	amountPaid := totalAmount.Add(changeAmount)
+
+	balances := make([]int, len(details))
+	for i, d := range details {
+		if d.VariantID != nil {
+			var variantStock int
+			err := tx.QueryRow("UPDATE product_variants SET stock = stock - $1 WHERE id = $2 AND stock >= $1 RETURNING stock", d.Quantity, *d.VariantID).
+				Scan(&variantStock)
+			if err == sql.ErrNoRows {
+				if err := tx.QueryRow("SELECT stock FROM product_variants WHERE id = $1", *d.VariantID).Scan(&variantStock); err != nil {
+					return nil, err
+				}
+				return nil, &models.ErrInsufficientStock{Items: []models.InsufficientStockItem{{
+					ProductID:   d.ProductID,
+					ProductName: d.ProductName,
+					VariantID:   d.VariantID,
+					VariantName: d.VariantName,
+					Requested:   d.Quantity,
+					Available:   variantStock,
+				}}}
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Stok produk bervarian adalah jumlah stok variannya, jadi ikut berkurang
+		// Kondisi stock >= qty tetap dicek saat update sebagai pengaman jika baris tidak di-lock
+		err := tx.QueryRow("UPDATE products SET stock = stock - $1, version = version + 1 WHERE id = $2 AND stock >= $1 RETURNING stock", d.Quantity, d.ProductID).
+			Scan(&balances[i])
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		if err == sql.ErrNoRows {
+			var stock int
+			if err := tx.QueryRow("SELECT stock FROM products WHERE id = $1", d.ProductID).Scan(&stock); err != nil {
+				return nil, err
+			}
+			return nil, &models.ErrInsufficientStock{Items: []models.InsufficientStockItem{{
+				ProductID:   d.ProductID,
+				ProductName: d.ProductName,
+				Requested:   d.Quantity,
+				Available:   stock,
+			}}}
+		}
 	}
 
 	var transactionID int
+	var createdAt time.Time
 	// Asumsi kolom created_at memiliki default NOW()
-	err = tx.QueryRow("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id", totalAmount).Scan(&transactionID)
+	err = tx.QueryRow(`
+		INSERT INTO transactions (total_amount, discount_amount, tax_amount, amount_paid, change_amount)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		totalAmount, discountAmount, taxAmount, amountPaid, changeAmount).Scan(&transactionID, &createdAt)
 	if err != nil {
 		return nil, err
 	}
 
 	for i := range details {
 		details[i].TransactionID = transactionID
-		_, err = tx.Exec("INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES ($1, $2, $3, $4)",
-			transactionID, details[i].ProductID, details[i].Quantity, details[i].Subtotal)
+		err = tx.QueryRow(`
+			INSERT INTO transaction_details (transaction_id, product_id, product_name, variant_id, variant_name, unit_price, unit_cost, quantity, subtotal,
+				discount_amount, tax_name, tax_rate_bp, tax_mode, tax_amount, total)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			RETURNING id`,
+			transactionID, details[i].ProductID, details[i].ProductName, details[i].VariantID, details[i].VariantName, details[i].UnitPrice, details[i].UnitCost, details[i].Quantity, details[i].Subtotal,
+			details[i].DiscountAmount, details[i].TaxName, details[i].TaxRateBP, details[i].TaxMode, details[i].TaxAmount, details[i].Total).Scan(&details[i].ID)
 		if err != nil {
 			return nil, err
 		}
+
+		err = recordStockMovement(tx, models.StockMovement{
+			ProductID:    details[i].ProductID,
+			VariantID:    details[i].VariantID,
+			Reason:       models.StockReasonSale,
+			Quantity:     -details[i].Quantity,
+			BalanceAfter: balances[i],
+			ReferenceID:  &transactionID,
+			Actor:        actor,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = recordStockAlert(tx, details[i].ProductID, details[i].ProductName,
+			balances[i]+details[i].Quantity, balances[i], minStocks[i], transactionID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range details[i].Discounts {
+			_, err = tx.Exec("INSERT INTO transaction_detail_discounts (transaction_detail_id, promotion_id, promotion_name, amount) VALUES ($1, $2, $3, $4)",
+				details[i].ID, d.PromotionID, d.PromotionName, d.Amount)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	for i := range payments {
+		payments[i].TransactionID = transactionID
+		err = tx.QueryRow("INSERT INTO payments (transaction_id, method, amount, change_amount, reference) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+			transactionID, payments[i].Method, payments[i].Amount, payments[i].ChangeAmount, payments[i].Reference).Scan(&payments[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.Transaction{
+		ID:             transactionID,
+		TotalAmount:    totalAmount,
+		DiscountAmount: discountAmount,
+		TaxAmount:      taxAmount,
+		AmountPaid:     amountPaid,
+		ChangeAmount:   changeAmount,
+		CreatedAt:      createdAt,
+		Details:        details,
+		Payments:       payments,
+	}, nil
+}
+
+// resolveCheckoutItems mengisi product_id item checkout yang hanya berisi barcode atau variant_id.
+// Jika product_id dan variant_id sama-sama diisi, varian harus milik produk tersebut.
+func resolveCheckoutItems(tx *sql.Tx, items []models.CheckoutItem) ([]models.CheckoutItem, error) {
+	resolved := make([]models.CheckoutItem, len(items))
+	for i, item := range items {
+		resolved[i] = item
+		switch {
+		case item.VariantID > 0:
+			var productID int
+			err := tx.QueryRow("SELECT product_id FROM product_variants WHERE id = $1", item.VariantID).Scan(&productID)
+			if err == sql.ErrNoRows || (err == nil && item.ProductID > 0 && item.ProductID != productID) {
+				return nil, fmt.Errorf("%w: variant id %d", models.ErrVariantNotFound, item.VariantID)
+			}
+			if err != nil {
+				return nil, err
+			}
+			resolved[i].ProductID = productID
+
+		case item.ProductID == 0 && item.Barcode != "":
+			err := tx.QueryRow("SELECT product_id FROM product_barcodes WHERE barcode = $1", item.Barcode).Scan(&resolved[i].ProductID)
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("%w: barcode %s", models.ErrProductNotFound, item.Barcode)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// mergeCheckoutItems menjumlahkan quantity untuk produk/varian yang sama dan mengurutkan hasilnya
+// berdasarkan product_id lalu variant_id, sehingga baris selalu di-lock dengan urutan yang sama (mencegah deadlock).
+func mergeCheckoutItems(items []models.CheckoutItem) []models.CheckoutItem {
+	type key struct{ productID, variantID int }
+	qtyByItem := make(map[key]int, len(items))
+	for _, item := range items {
+		qtyByItem[key{item.ProductID, item.VariantID}] += item.Quantity
+	}
+
+	merged := make([]models.CheckoutItem, 0, len(qtyByItem))
+	for k, qty := range qtyByItem {
+		merged = append(merged, models.CheckoutItem{ProductID: k.productID, VariantID: k.variantID, Quantity: qty})
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].ProductID != merged[j].ProductID {
+			return merged[i].ProductID < merged[j].ProductID
+		}
+		return merged[i].VariantID < merged[j].VariantID
+	})
+
+	return merged
+}
+
+// GetTransactions mengembalikan daftar transaksi (tanpa detail) sesuai filter beserta total data sebelum pagination
+func (r *TransactionRepository) GetTransactions(filter models.TransactionFilter) ([]models.Transaction, int, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.StartDate != "" {
+		addCondition("DATE(t.created_at) >= $%d", filter.StartDate)
+	}
+	if filter.EndDate != "" {
+		addCondition("DATE(t.created_at) <= $%d", filter.EndDate)
+	}
+	if filter.MinAmount != nil {
+		addCondition("t.total_amount >= $%d", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		addCondition("t.total_amount <= $%d", *filter.MaxAmount)
+	}
+	if filter.ProductID > 0 {
+		addCondition("EXISTS (SELECT 1 FROM transaction_details td WHERE td.transaction_id = t.id AND td.product_id = $%d)", filter.ProductID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM transactions t"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT t.id, t.total_amount, t.discount_amount, t.tax_amount, t.amount_paid, t.change_amount, t.created_at FROM transactions t" + where +
+		fmt.Sprintf(" ORDER BY t.created_at DESC, t.id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transactions := make([]models.Transaction, 0)
+	for rows.Next() {
+		var t models.Transaction
+		if err := rows.Scan(&t.ID, &t.TotalAmount, &t.DiscountAmount, &t.TaxAmount, &t.AmountPaid, &t.ChangeAmount, &t.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		transactions = append(transactions, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, total, nil
+}
+
+// GetTransactionByID mengembalikan satu transaksi lengkap dengan detail dan nama produk
+func (r *TransactionRepository) GetTransactionByID(id int) (*models.Transaction, error) {
+	var t models.Transaction
+	err := r.db.QueryRow("SELECT id, total_amount, discount_amount, tax_amount, amount_paid, change_amount, created_at FROM transactions WHERE id = $1", id).
+		Scan(&t.ID, &t.TotalAmount, &t.DiscountAmount, &t.TaxAmount, &t.AmountPaid, &t.ChangeAmount, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTransactionNotFound
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	res = &models.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		Details:     details,
+	rows, err := r.db.Query(`
+		SELECT td.id, td.transaction_id, COALESCE(td.product_id, 0), td.product_name, td.variant_id, COALESCE(td.variant_name, ''), td.unit_price, td.unit_cost, td.quantity, td.subtotal, td.discount_amount,
+			td.tax_name, td.tax_rate_bp, td.tax_mode, td.tax_amount, td.total,
+			COALESCE((SELECT SUM(rd.quantity) FROM refund_details rd WHERE rd.transaction_detail_id = td.id), 0)
+		FROM transaction_details td
+		WHERE td.transaction_id = $1
+		ORDER BY td.id
+	`, id)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return res, nil
+	t.Details = make([]models.TransactionDetail, 0)
+	for rows.Next() {
+		var d models.TransactionDetail
+		if err := rows.Scan(&d.ID, &d.TransactionID, &d.ProductID, &d.ProductName, &d.VariantID, &d.VariantName, &d.UnitPrice, &d.UnitCost, &d.Quantity, &d.Subtotal, &d.DiscountAmount,
+			&d.TaxName, &d.TaxRateBP, &d.TaxMode, &d.TaxAmount, &d.Total, &d.RefundedQty); err != nil {
+			return nil, err
+		}
+		t.Details = append(t.Details, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	discountRows, err := r.db.Query(`
+		SELECT tdd.transaction_detail_id, COALESCE(tdd.promotion_id, 0), tdd.promotion_name, tdd.amount
+		FROM transaction_detail_discounts tdd
+		JOIN transaction_details td ON td.id = tdd.transaction_detail_id
+		WHERE td.transaction_id = $1
+		ORDER BY tdd.id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer discountRows.Close()
+
+	detailIndex := make(map[int]int, len(t.Details))
+	for i, d := range t.Details {
+		detailIndex[d.ID] = i
+	}
+	for discountRows.Next() {
+		var detailID int
+		var d models.LineDiscount
+		if err := discountRows.Scan(&detailID, &d.PromotionID, &d.PromotionName, &d.Amount); err != nil {
+			return nil, err
+		}
+		if i, ok := detailIndex[detailID]; ok {
+			t.Details[i].Discounts = append(t.Details[i].Discounts, d)
+		}
+	}
+
+	if err := discountRows.Err(); err != nil {
+		return nil, err
+	}
+
+	paymentRows, err := r.db.Query(`
+		SELECT id, transaction_id, method, amount, change_amount, reference
+		FROM payments
+		WHERE transaction_id = $1
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer paymentRows.Close()
+
+	for paymentRows.Next() {
+		var p models.Payment
+		if err := paymentRows.Scan(&p.ID, &p.TransactionID, &p.Method, &p.Amount, &p.ChangeAmount, &p.Reference); err != nil {
+			return nil, err
+		}
+		t.Payments = append(t.Payments, p)
+	}
+
+	if err := paymentRows.Err(); err != nil {
+		return nil, err
+	}
+
+	refundRows, err := r.db.Query(`
+		SELECT id, transaction_id, type, total_amount, method, reason, created_at
+		FROM refunds
+		WHERE transaction_id = $1
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer refundRows.Close()
+
+	for refundRows.Next() {
+		var rf models.Refund
+		if err := refundRows.Scan(&rf.ID, &rf.TransactionID, &rf.Type, &rf.TotalAmount, &rf.Method, &rf.Reason, &rf.CreatedAt); err != nil {
+			return nil, err
+		}
+		t.Refunds = append(t.Refunds, rf)
+	}
+
+	if err := refundRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
 }
 
 // Mengembalikan produk terlaris hari ini (nama + qty terjual) dengan menghitung dari transaction_details
-func (r *TransactionRepository) GetTopSellingProduct() (*models.TopSellingProduct, error) {
+func (r *TransactionRepository) GetTopSellingProduct(categoryID int) (*models.TopSellingProduct, error) {
 	var (
 		name    string
 		qtySold int
+		args    []any
 	)
 
 	// Hitung qty terjual per produk dari transaction_details yang terjadi hari ini
-	// Bergantung pada kolom created_at di tabel transactions
+	// Bergantung pada kolom created_at di tabel transactions; nama produk diambil dari snapshot saat checkout
 	query := `
-		SELECT p.name, COALESCE(SUM(td.quantity), 0) AS qty_terjual
+		SELECT td.product_name, COALESCE(SUM(td.quantity), 0) AS qty_terjual
 		FROM transaction_details td
 		JOIN transactions t ON t.id = td.transaction_id
-		JOIN products p ON p.id = td.product_id
-		WHERE DATE(t.created_at) = CURRENT_DATE
-		GROUP BY p.name
+		WHERE DATE(t.created_at) = CURRENT_DATE` + categoryLineFilter(categoryID, &args) + `
+		GROUP BY td.product_name
 		ORDER BY qty_terjual DESC
 		LIMIT 1
 	`
 
-	err := r.db.QueryRow(query).Scan(&name, &qtySold)
+	err := r.db.QueryRow(query, args...).Scan(&name, &qtySold)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("no products found")
 	}
@@ -120,71 +585,271 @@ func (r *TransactionRepository) GetTopSellingProduct() (*models.TopSellingProduc
 	}, nil
 }
 
-// Ringkasan penjualan "hari ini": total revenue dan jumlah transaksi
-func (r *TransactionRepository) GetSalesSummary() (int, int, error) {
-	var totalRevenue int
-	var totalTransaksi int
+// Ringkasan penjualan "hari ini": revenue kotor, diskon, refund, revenue bersih dan jumlah transaksi yang tidak di-void.
+// categoryID > 0 membatasi ringkasan ke produk dalam kategori tersebut beserta sub-kategorinya.
+func (r *TransactionRepository) GetSalesSummary(categoryID int) (*models.SalesTotals, error) {
+	return r.getSalesTotals("DATE(created_at) = CURRENT_DATE", categoryID)
+}
+
+// Ringkasan penjualan berdasarkan rentang tanggal [startDate, endDate] (format: YYYY-MM-DD).
+// Refund/void yang terjadi di dalam rentang ikut dikurangkan dari revenue bersih.
+func (r *TransactionRepository) GetSalesSummaryByRange(startDate, endDate string, categoryID int) (*models.SalesTotals, error) {
+	return r.getSalesTotals("DATE(created_at) >= $1 AND DATE(created_at) <= $2", categoryID, startDate, endDate)
+}
+
+// getSalesTotals menghitung agregat penjualan untuk kondisi periode pada kolom created_at
+func (r *TransactionRepository) getSalesTotals(period string, categoryID int, args ...any) (*models.SalesTotals, error) {
+	if categoryID > 0 {
+		return r.getCategorySalesTotals(period, categoryID, args...)
+	}
+
+	var totals models.SalesTotals
 
-	// Hitung total revenue hari ini
-	err := r.db.QueryRow("SELECT COALESCE(SUM(total_amount), 0) FROM transactions WHERE DATE(created_at) = CURRENT_DATE").Scan(&totalRevenue)
+	query := `
+		SELECT
+			(SELECT COALESCE(SUM(total_amount + discount_amount), 0) FROM transactions WHERE ` + period + `),
+			(SELECT COALESCE(SUM(discount_amount), 0) FROM transactions WHERE ` + period + `),
+			(SELECT COALESCE(SUM(total_amount), 0) FROM refunds WHERE ` + period + `),
+			(SELECT COUNT(*) FROM transactions t WHERE ` + period + `
+				AND NOT EXISTS (SELECT 1 FROM refunds rf WHERE rf.transaction_id = t.id AND rf.type = 'void'))
+	`
+	err := r.db.QueryRow(query, args...).Scan(&totals.GrossRevenue, &totals.TotalDiscount, &totals.TotalRefund, &totals.TotalTransaksi)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
 	}
 
-	// Hitung total transaksi hari ini
-	err = r.db.QueryRow("SELECT COUNT(*) FROM transactions WHERE DATE(created_at) = CURRENT_DATE").Scan(&totalTransaksi)
+	totals.NetRevenue = totals.GrossRevenue.Sub(totals.TotalDiscount).Sub(totals.TotalRefund)
+
+	// Rincian per metode pembayaran untuk rekonsiliasi laci kasir. Refund/void dikurangkan dari metode
+	// pengembaliannya pada periode refund terjadi, sama seperti total_refund.
+	rows, err := r.db.Query(`
+		SELECT method, SUM(payment_count)::int, SUM(amount), SUM(change_amount), SUM(refund_amount)
+		FROM (
+			SELECT p.method, COUNT(*) AS payment_count, SUM(p.amount) AS amount, SUM(p.change_amount) AS change_amount, 0 AS refund_amount
+			FROM payments p
+			WHERE p.transaction_id IN (SELECT id FROM transactions WHERE `+period+`)
+			GROUP BY p.method
+			UNION ALL
+			SELECT rf.method, 0, 0, 0, SUM(rf.total_amount)
+			FROM refunds rf
+			WHERE `+period+`
+			GROUP BY rf.method
+		) m
+		GROUP BY method
+		ORDER BY method
+	`, args...)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals.Payments = make([]models.PaymentMethodTotal, 0)
+	for rows.Next() {
+		var p models.PaymentMethodTotal
+		if err := rows.Scan(&p.Method, &p.Count, &p.Amount, &p.ChangeAmount, &p.RefundAmount); err != nil {
+			return nil, err
+		}
+		p.NetAmount = p.Amount.Sub(p.ChangeAmount).Sub(p.RefundAmount)
+		totals.Payments = append(totals.Payments, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return totalRevenue, totalTransaksi, nil
+	return &totals, nil
 }
 
-// Ringkasan penjualan berdasarkan rentang tanggal [startDate, endDate] (format: YYYY-MM-DD)
-func (r *TransactionRepository) GetSalesSummaryByRange(startDate, endDate string) (int, int, error) {
-	var totalRevenue int
-	var totalTransaksi int
+// getCategorySalesTotals menghitung agregat penjualan dari baris transaksi produk dalam kategori (beserta
+// sub-kategorinya). Transaksi dihitung jika memiliki minimal satu baris kategori tersebut. Pembayaran
+// tidak bisa dipecah per baris, sehingga rincian per metode pembayaran dikosongkan.
+func (r *TransactionRepository) getCategorySalesTotals(period string, categoryID int, args ...any) (*models.SalesTotals, error) {
+	var totals models.SalesTotals
+	lineFilter := categoryLineFilter(categoryID, &args)
 
-	// Hitung total revenue pada rentang tanggal
-	err := r.db.QueryRow(`
-		SELECT COALESCE(SUM(total_amount), 0)
-		FROM transactions
-		WHERE DATE(created_at) >= $1 AND DATE(created_at) <= $2
-	`, startDate, endDate).Scan(&totalRevenue)
+	query := `
+		SELECT
+			(SELECT COALESCE(SUM(td.total + td.discount_amount), 0) FROM transaction_details td
+				WHERE td.transaction_id IN (SELECT id FROM transactions WHERE ` + period + `)` + lineFilter + `),
+			(SELECT COALESCE(SUM(td.discount_amount), 0) FROM transaction_details td
+				WHERE td.transaction_id IN (SELECT id FROM transactions WHERE ` + period + `)` + lineFilter + `),
+			(SELECT COALESCE(SUM(rd.amount), 0) FROM refund_details rd
+				JOIN transaction_details td ON td.id = rd.transaction_detail_id
+				WHERE rd.refund_id IN (SELECT id FROM refunds WHERE ` + period + `)` + lineFilter + `),
+			(SELECT COUNT(*) FROM transactions t WHERE ` + period + `
+				AND NOT EXISTS (SELECT 1 FROM refunds rf WHERE rf.transaction_id = t.id AND rf.type = 'void')
+				AND EXISTS (SELECT 1 FROM transaction_details td WHERE td.transaction_id = t.id` + lineFilter + `))
+	`
+	err := r.db.QueryRow(query, args...).Scan(&totals.GrossRevenue, &totals.TotalDiscount, &totals.TotalRefund, &totals.TotalTransaksi)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
+	}
+
+	totals.NetRevenue = totals.GrossRevenue.Sub(totals.TotalDiscount).Sub(totals.TotalRefund)
+	totals.Payments = make([]models.PaymentMethodTotal, 0)
+	return &totals, nil
+}
+
+// categoryLineFilter membatasi baris transaction_details (alias td) ke produk dalam kategori beserta
+// sub-kategorinya dan menambahkan id kategori ke args. categoryID 0 berarti tanpa filter.
+func categoryLineFilter(categoryID int, args *[]any) string {
+	if categoryID <= 0 {
+		return ""
 	}
+	*args = append(*args, categoryID)
+	return `
+				AND td.product_id IN (SELECT id FROM products WHERE category_id IN (` + categorySubtreeQuery(fmt.Sprintf("$%d", len(*args))) + `))`
+}
+
+// GetProfitReport menghitung HPP dan laba kotor "hari ini" per produk dan per kategori
+func (r *TransactionRepository) GetProfitReport(categoryID int) (*models.ProfitReport, error) {
+	return r.getProfitReport("DATE(created_at) = CURRENT_DATE", categoryID)
+}
 
-	// Hitung total transaksi pada rentang tanggal
-	err = r.db.QueryRow(`
-		SELECT COUNT(*)
-		FROM transactions
-		WHERE DATE(created_at) >= $1 AND DATE(created_at) <= $2
-	`, startDate, endDate).Scan(&totalTransaksi)
+// GetProfitReportByRange menghitung HPP dan laba kotor pada rentang tanggal [startDate, endDate] (format: YYYY-MM-DD)
+func (r *TransactionRepository) GetProfitReportByRange(startDate, endDate string, categoryID int) (*models.ProfitReport, error) {
+	return r.getProfitReport("DATE(created_at) >= $1 AND DATE(created_at) <= $2", categoryID, startDate, endDate)
+}
+
+// getProfitReport menghitung penjualan bersih (setelah diskon, tanpa pajak), HPP dari snapshot unit_cost
+// dan laba kotor. Seperti getSalesTotals, refund/void yang terjadi di dalam periode ikut dikurangkan.
+// Kategori produk dibaca dari data produk saat ini.
+func (r *TransactionRepository) getProfitReport(period string, categoryID int, args ...any) (*models.ProfitReport, error) {
+	lineFilter := categoryLineFilter(categoryID, &args)
+	rows, err := r.db.Query(`
+		WITH lines AS (
+			SELECT td.product_id, td.product_name, td.variant_id, td.variant_name, td.quantity AS qty,
+				td.total - td.tax_amount AS sales, td.unit_cost * td.quantity AS cogs
+			FROM transaction_details td
+			WHERE td.transaction_id IN (SELECT id FROM transactions WHERE `+period+`)`+lineFilter+`
+			UNION ALL
+			SELECT td.product_id, td.product_name, td.variant_id, td.variant_name, -rd.quantity,
+				-(rd.amount - rd.tax_amount), -(td.unit_cost * rd.quantity)
+			FROM refund_details rd
+			JOIN transaction_details td ON td.id = rd.transaction_detail_id
+			WHERE rd.refund_id IN (SELECT id FROM refunds WHERE `+period+`)`+lineFilter+`
+		)
+		SELECT l.product_id, l.product_name, l.variant_id, COALESCE(l.variant_name, ''), p.category_id, COALESCE(c.name, ''),
+			SUM(l.qty), COALESCE(SUM(l.sales), 0), COALESCE(SUM(l.cogs), 0)
+		FROM lines l
+		LEFT JOIN products p ON p.id = l.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		GROUP BY l.product_id, l.product_name, l.variant_id, l.variant_name, p.category_id, c.name
+		ORDER BY l.product_name, l.product_id, l.variant_name, l.variant_id
+	`, args...)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := models.ProfitReport{
+		NetSales:   models.NewMoney(0),
+		COGS:       models.NewMoney(0),
+		Products:   make([]models.ProfitLine, 0),
+		Categories: make([]models.ProfitLine, 0),
+		Variants:   make([]models.ProfitLine, 0),
+	}
+	categoryIndex := make(map[int]int)
+	for rows.Next() {
+		var line models.ProfitLine
+		var variantID, categoryID *int
+		var variantName, categoryName string
+		if err := rows.Scan(&line.ID, &line.Name, &variantID, &variantName, &categoryID, &categoryName, &line.QtySold, &line.NetSales, &line.COGS); err != nil {
+			return nil, err
+		}
+		line.GrossProfit = line.NetSales.Sub(line.COGS)
+		line.MarginPercent = models.MarginPercent(line.GrossProfit, line.NetSales)
+
+		if variantID != nil {
+			report.Variants = append(report.Variants, models.ProfitLine{
+				ID:            variantID,
+				ProductID:     line.ID,
+				Name:          line.Name + " - " + variantName,
+				QtySold:       line.QtySold,
+				NetSales:      line.NetSales,
+				COGS:          line.COGS,
+				GrossProfit:   line.GrossProfit,
+				MarginPercent: line.MarginPercent,
+			})
+		}
+
+		// Baris varian dari produk yang sama berurutan, jadi cukup digabung ke baris produk terakhir
+		if n := len(report.Products); n > 0 && sameProfitProduct(report.Products[n-1], line) {
+			product := &report.Products[n-1]
+			product.QtySold += line.QtySold
+			product.NetSales = product.NetSales.Add(line.NetSales)
+			product.COGS = product.COGS.Add(line.COGS)
+			product.GrossProfit = product.NetSales.Sub(product.COGS)
+			product.MarginPercent = models.MarginPercent(product.GrossProfit, product.NetSales)
+		} else {
+			report.Products = append(report.Products, line)
+		}
+
+		report.NetSales = report.NetSales.Add(line.NetSales)
+		report.COGS = report.COGS.Add(line.COGS)
+
+		// Produk yang sudah dihapus dikumpulkan di kategori dengan id null (key 0)
+		key := 0
+		if categoryID != nil {
+			key = *categoryID
+		}
+		i, ok := categoryIndex[key]
+		if !ok {
+			i = len(report.Categories)
+			categoryIndex[key] = i
+			report.Categories = append(report.Categories, models.ProfitLine{
+				ID:       categoryID,
+				Name:     categoryName,
+				NetSales: models.NewMoney(0),
+				COGS:     models.NewMoney(0),
+			})
+		}
+		category := &report.Categories[i]
+		category.QtySold += line.QtySold
+		category.NetSales = category.NetSales.Add(line.NetSales)
+		category.COGS = category.COGS.Add(line.COGS)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return totalRevenue, totalTransaksi, nil
+	for i := range report.Categories {
+		category := &report.Categories[i]
+		category.GrossProfit = category.NetSales.Sub(category.COGS)
+		category.MarginPercent = models.MarginPercent(category.GrossProfit, category.NetSales)
+	}
+	report.GrossProfit = report.NetSales.Sub(report.COGS)
+	report.MarginPercent = models.MarginPercent(report.GrossProfit, report.NetSales)
+
+	return &report, nil
+}
+
+// sameProfitProduct membandingkan product_id dan nama; produk yang sudah dihapus (id null) dibedakan dari namanya
+func sameProfitProduct(a, b models.ProfitLine) bool {
+	if a.Name != b.Name || (a.ID == nil) != (b.ID == nil) {
+		return false
+	}
+	return a.ID == nil || *a.ID == *b.ID
 }
 
 // Produk terlaris berdasarkan rentang tanggal [startDate, endDate]
-func (r *TransactionRepository) GetTopSellingProductByRange(startDate, endDate string) (*models.TopSellingProduct, error) {
+func (r *TransactionRepository) GetTopSellingProductByRange(startDate, endDate string, categoryID int) (*models.TopSellingProduct, error) {
 	var name string
 	var qtySold int
+	args := []any{startDate, endDate}
 
 	query := `
-		SELECT p.name, COALESCE(SUM(td.quantity), 0) AS qty_terjual
+		SELECT td.product_name, COALESCE(SUM(td.quantity), 0) AS qty_terjual
 		FROM transaction_details td
 		JOIN transactions t ON t.id = td.transaction_id
-		JOIN products p ON p.id = td.product_id
-		WHERE DATE(t.created_at) >= $1 AND DATE(t.created_at) <= $2
-		GROUP BY p.name
+		WHERE DATE(t.created_at) >= $1 AND DATE(t.created_at) <= $2` + categoryLineFilter(categoryID, &args) + `
+		GROUP BY td.product_name
 		ORDER BY qty_terjual DESC
 		LIMIT 1
 	`
 
-	err := r.db.QueryRow(query, startDate, endDate).Scan(&name, &qtySold)
+	err := r.db.QueryRow(query, args...).Scan(&name, &qtySold)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("no products found in range")
 	}
@@ -197,3 +862,53 @@ func (r *TransactionRepository) GetTopSellingProductByRange(startDate, endDate s
 		QtySold: qtySold,
 	}, nil
 }
+
+// GetTaxReport mengelompokkan pajak per tarif (snapshot nama/rate/mode saat checkout) untuk rentang
+// tanggal [startDate, endDate]. Pajak atas refund/void yang terjadi di dalam rentang ikut dikurangkan.
+func (r *TransactionRepository) GetTaxReport(startDate, endDate string) ([]models.TaxReportLine, error) {
+	query := `
+		WITH sold AS (
+			SELECT td.tax_name, td.tax_rate_bp, td.tax_mode,
+				SUM(td.total - td.tax_amount) AS taxable_amount, SUM(td.tax_amount) AS tax_amount
+			FROM transaction_details td
+			JOIN transactions t ON t.id = td.transaction_id
+			WHERE DATE(t.created_at) >= $1 AND DATE(t.created_at) <= $2 AND td.tax_name <> ''
+			GROUP BY td.tax_name, td.tax_rate_bp, td.tax_mode
+		), refunded AS (
+			SELECT td.tax_name, td.tax_rate_bp, td.tax_mode, SUM(rd.tax_amount) AS tax_amount
+			FROM refund_details rd
+			JOIN refunds rf ON rf.id = rd.refund_id
+			JOIN transaction_details td ON td.id = rd.transaction_detail_id
+			WHERE DATE(rf.created_at) >= $1 AND DATE(rf.created_at) <= $2 AND td.tax_name <> ''
+			GROUP BY td.tax_name, td.tax_rate_bp, td.tax_mode
+		)
+		SELECT COALESCE(s.tax_name, f.tax_name), COALESCE(s.tax_rate_bp, f.tax_rate_bp), COALESCE(s.tax_mode, f.tax_mode),
+			COALESCE(s.taxable_amount, 0), COALESCE(s.tax_amount, 0), COALESCE(f.tax_amount, 0)
+		FROM sold s
+		FULL OUTER JOIN refunded f
+			ON f.tax_name = s.tax_name AND f.tax_rate_bp = s.tax_rate_bp AND f.tax_mode = s.tax_mode
+		ORDER BY 1, 2, 3
+	`
+
+	rows, err := r.db.Query(query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []models.TaxReportLine{}
+	for rows.Next() {
+		var l models.TaxReportLine
+		if err := rows.Scan(&l.TaxName, &l.RateBP, &l.Mode, &l.TaxableAmount, &l.TaxAmount, &l.RefundedTax); err != nil {
+			return nil, err
+		}
+		l.NetTax = l.TaxAmount.Sub(l.RefundedTax)
+		lines = append(lines, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
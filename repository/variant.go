@@ -0,0 +1,602 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"simple-crud/models"
+)
+
+type VariantRepository struct {
+	db *sql.DB
+}
+
+func NewVariantRepository(db *sql.DB) *VariantRepository {
+	return &VariantRepository{db: db}
+}
+
+// GetOptionTypes mengembalikan tipe opsi produk beserta nilainya sesuai urutan position
+func (r *VariantRepository) GetOptionTypes(productID int) ([]models.ProductOptionType, error) {
+	if err := requireProduct(r.db, productID); err != nil {
+		return nil, err
+	}
+	return getOptionTypes(r.db, productID)
+}
+
+func getOptionTypes(q queryer, productID int) ([]models.ProductOptionType, error) {
+	rows, err := q.Query(`
+		SELECT ot.id, ot.name, ot.position, ov.id, ov.value, ov.position
+		FROM product_option_types ot
+		LEFT JOIN product_option_values ov ON ov.option_type_id = ot.id
+		WHERE ot.product_id = $1
+		ORDER BY ot.position, ot.id, ov.position, ov.id
+	`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := make([]models.ProductOptionType, 0)
+	for rows.Next() {
+		var t models.ProductOptionType
+		var valueID, valuePosition sql.NullInt64
+		var value sql.NullString
+		if err := rows.Scan(&t.ID, &t.Name, &t.Position, &valueID, &value, &valuePosition); err != nil {
+			return nil, err
+		}
+		if len(types) == 0 || types[len(types)-1].ID != t.ID {
+			t.ProductID = productID
+			t.Values = make([]models.ProductOptionValue, 0)
+			types = append(types, t)
+		}
+		if valueID.Valid {
+			last := &types[len(types)-1]
+			last.Values = append(last.Values, models.ProductOptionValue{
+				ID:           int(valueID.Int64),
+				OptionTypeID: t.ID,
+				Value:        value.String,
+				Position:     int(valuePosition.Int64),
+			})
+		}
+	}
+
+	return types, rows.Err()
+}
+
+// CreateOptionType menambah tipe opsi baru beserta nilainya. Tipe opsi hanya bisa ditambah sebelum produk memiliki varian,
+// karena setiap varian wajib punya satu nilai untuk setiap tipe opsi.
+func (r *VariantRepository) CreateOptionType(productID int, req models.OptionTypeRequest) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockVariantProduct(tx, productID); err != nil {
+		return 0, err
+	}
+	if err := requireNoVariantsYet(tx, productID); err != nil {
+		return 0, err
+	}
+
+	var exists bool
+	var position int
+	err = tx.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM product_option_types WHERE product_id = $1 AND LOWER(name) = LOWER($2)),
+			COALESCE((SELECT MAX(position) + 1 FROM product_option_types WHERE product_id = $1), 0)
+	`, productID, req.Name).Scan(&exists, &position)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, fmt.Errorf("%w: tipe opsi %q sudah ada", models.ErrInvalidVariant, req.Name)
+	}
+
+	var id int
+	err = tx.QueryRow("INSERT INTO product_option_types (product_id, name, position) VALUES ($1, $2, $3) RETURNING id",
+		productID, req.Name, position).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertOptionValues(tx, id, req.Values); err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// AddOptionValues menambah nilai ke tipe opsi yang sudah ada; aman dilakukan walaupun produk sudah memiliki varian
+func (r *VariantRepository) AddOptionValues(productID, optionTypeID int, values []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockVariantProduct(tx, productID); err != nil {
+		return err
+	}
+	if err := requireOptionType(tx, productID, optionTypeID); err != nil {
+		return err
+	}
+	if err := insertOptionValues(tx, optionTypeID, values); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteOptionType menghapus tipe opsi beserta nilainya, hanya jika produk belum memiliki varian
+func (r *VariantRepository) DeleteOptionType(productID, optionTypeID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockVariantProduct(tx, productID); err != nil {
+		return err
+	}
+	if err := requireOptionType(tx, productID, optionTypeID); err != nil {
+		return err
+	}
+	if err := requireNoVariantsYet(tx, productID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM product_option_types WHERE id = $1", optionTypeID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertOptionValues(tx *sql.Tx, optionTypeID int, values []string) error {
+	var position int
+	err := tx.QueryRow("SELECT COALESCE(MAX(position) + 1, 0) FROM product_option_values WHERE option_type_id = $1", optionTypeID).Scan(&position)
+	if err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		var exists bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM product_option_values WHERE option_type_id = $1 AND LOWER(value) = LOWER($2))",
+			optionTypeID, value).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w: value %q sudah ada", models.ErrInvalidVariant, value)
+		}
+
+		if _, err := tx.Exec("INSERT INTO product_option_values (option_type_id, value, position) VALUES ($1, $2, $3)",
+			optionTypeID, value, position); err != nil {
+			return err
+		}
+		position++
+	}
+	return nil
+}
+
+// GetVariants mengembalikan seluruh varian produk, urut berdasarkan id
+func (r *VariantRepository) GetVariants(productID int) ([]models.ProductVariant, error) {
+	if err := requireProduct(r.db, productID); err != nil {
+		return nil, err
+	}
+	return getVariants(r.db, productID, 0)
+}
+
+func (r *VariantRepository) GetVariant(productID, variantID int) (*models.ProductVariant, error) {
+	variants, err := getVariants(r.db, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) == 0 {
+		return nil, models.ErrVariantNotFound
+	}
+	return &variants[0], nil
+}
+
+// getVariants memuat varian produk (atau satu varian jika variantID > 0) beserta opsi dan namanya
+func getVariants(q queryer, productID, variantID int) ([]models.ProductVariant, error) {
+	args := []any{productID}
+	where := ""
+	if variantID > 0 {
+		args = append(args, variantID)
+		where = " AND v.id = $2"
+	}
+
+	rows, err := q.Query(`
+		SELECT v.id, COALESCE(v.sku, ''), v.price, COALESCE(v.price, p.price), v.stock, ot.name, ov.value
+		FROM product_variants v
+		JOIN products p ON p.id = v.product_id
+		JOIN product_variant_options vo ON vo.variant_id = v.id
+		JOIN product_option_values ov ON ov.id = vo.option_value_id
+		JOIN product_option_types ot ON ot.id = ov.option_type_id
+		WHERE v.product_id = $1`+where+`
+		ORDER BY v.id, ot.position, ot.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := make([]models.ProductVariant, 0)
+	names := make([][]string, 0)
+	for rows.Next() {
+		var v models.ProductVariant
+		var optionName, optionValue string
+		if err := rows.Scan(&v.ID, &v.SKU, &v.Price, &v.EffectivePrice, &v.Stock, &optionName, &optionValue); err != nil {
+			return nil, err
+		}
+		if len(variants) == 0 || variants[len(variants)-1].ID != v.ID {
+			v.ProductID = productID
+			v.Options = make(map[string]string)
+			variants = append(variants, v)
+			names = append(names, nil)
+		}
+		variants[len(variants)-1].Options[optionName] = optionValue
+		names[len(names)-1] = append(names[len(names)-1], optionValue)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range variants {
+		variants[i].Name = strings.Join(names[i], " / ")
+	}
+	return variants, nil
+}
+
+// CreateVariant membuat varian baru. Stok varian menambah stok produk induk dan dicatat di ledger stok;
+// untuk varian pertama, stok produk yang sudah ada sebelumnya dipindahkan (dinolkan) lebih dulu.
+func (r *VariantRepository) CreateVariant(productID int, req models.VariantRequest, actor string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stock, err := lockVariantProduct(tx, productID)
+	if err != nil {
+		return 0, err
+	}
+
+	valueIDs, key, err := resolveVariantOptions(tx, productID, req.Options, 0)
+	if err != nil {
+		return 0, err
+	}
+	if err := requireUniqueSKU(tx, req.SKU, 0, 0); err != nil {
+		return 0, err
+	}
+
+	var hasVariants bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1)", productID).Scan(&hasVariants); err != nil {
+		return 0, err
+	}
+	if !hasVariants && stock != 0 {
+		if _, err := tx.Exec("UPDATE products SET stock = 0, version = version + 1 WHERE id = $1", productID); err != nil {
+			return 0, err
+		}
+		err = recordStockMovement(tx, models.StockMovement{
+			ProductID:    productID,
+			Reason:       models.StockReasonAdjustment,
+			Quantity:     -stock,
+			BalanceAfter: 0,
+			Actor:        actor,
+			Note:         "stok produk dipindah ke varian",
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	var id int
+	err = tx.QueryRow("INSERT INTO product_variants (product_id, sku, price, stock, option_key) VALUES ($1, NULLIF($2, ''), $3, $4, $5) RETURNING id",
+		productID, req.SKU, req.Price, req.Stock, key).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	for _, valueID := range valueIDs {
+		if _, err := tx.Exec("INSERT INTO product_variant_options (variant_id, option_value_id) VALUES ($1, $2)", id, valueID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := addVariantStock(tx, productID, id, req.Stock, actor, models.StockReasonInitial, "varian baru"); err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// UpdateVariant menimpa SKU, harga, stok dan opsi varian; selisih stok dicatat sebagai adjustment
+func (r *VariantRepository) UpdateVariant(productID, variantID int, req models.VariantRequest, actor string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockVariantProduct(tx, productID); err != nil {
+		return err
+	}
+
+	var oldStock int
+	err = tx.QueryRow("SELECT stock FROM product_variants WHERE id = $1 AND product_id = $2 FOR UPDATE", variantID, productID).Scan(&oldStock)
+	if err == sql.ErrNoRows {
+		return models.ErrVariantNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	valueIDs, key, err := resolveVariantOptions(tx, productID, req.Options, variantID)
+	if err != nil {
+		return err
+	}
+	if err := requireUniqueSKU(tx, req.SKU, 0, variantID); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE product_variants SET sku = NULLIF($2, ''), price = $3, stock = $4, option_key = $5 WHERE id = $1",
+		variantID, req.SKU, req.Price, req.Stock, key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM product_variant_options WHERE variant_id = $1", variantID); err != nil {
+		return err
+	}
+	for _, valueID := range valueIDs {
+		if _, err := tx.Exec("INSERT INTO product_variant_options (variant_id, option_value_id) VALUES ($1, $2)", variantID, valueID); err != nil {
+			return err
+		}
+	}
+
+	if err := addVariantStock(tx, productID, variantID, req.Stock-oldStock, actor, models.StockReasonAdjustment, "update varian"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteVariant menghapus varian; sisa stoknya dikurangkan dari stok produk induk
+func (r *VariantRepository) DeleteVariant(productID, variantID int, actor string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockVariantProduct(tx, productID); err != nil {
+		return err
+	}
+
+	var stock int
+	err = tx.QueryRow("DELETE FROM product_variants WHERE id = $1 AND product_id = $2 RETURNING stock", variantID, productID).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return models.ErrVariantNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	// variant_id di ledger menjadi null karena varian sudah dihapus
+	if err := addVariantStock(tx, productID, 0, -stock, actor, models.StockReasonAdjustment, fmt.Sprintf("hapus varian #%d", variantID)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockVariantProduct mengunci baris produk induk dan mengembalikan stoknya. Semua perubahan varian
+// mengunci produk lebih dulu, sama seperti checkout, sehingga urutan lock selalu produk lalu varian.
+func lockVariantProduct(tx *sql.Tx, productID int) (int, error) {
+	var stock int
+	err := tx.QueryRow("SELECT stock FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", productID).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return 0, models.ErrProductNotFound
+	}
+	return stock, err
+}
+
+// addVariantStock menambah stok produk induk sebesar delta perubahan stok varian dan mencatatnya di ledger
+func addVariantStock(tx *sql.Tx, productID, variantID, delta int, actor, reason, note string) error {
+	if delta == 0 {
+		return nil
+	}
+
+	var balance int
+	if err := tx.QueryRow("UPDATE products SET stock = stock + $1, version = version + 1 WHERE id = $2 RETURNING stock", delta, productID).Scan(&balance); err != nil {
+		return err
+	}
+
+	m := models.StockMovement{
+		ProductID:    productID,
+		Reason:       reason,
+		Quantity:     delta,
+		BalanceAfter: balance,
+		Actor:        actor,
+		Note:         note,
+	}
+	if variantID > 0 {
+		m.VariantID = &variantID
+	}
+	return recordStockMovement(tx, m)
+}
+
+// resolveVariantOptions mengubah map nama tipe opsi -> nilai menjadi id nilai opsi. Setiap tipe opsi produk wajib
+// punya tepat satu nilai, dan kombinasinya tidak boleh sama dengan varian lain (selain excludeVariantID).
+func resolveVariantOptions(tx *sql.Tx, productID int, options map[string]string, excludeVariantID int) ([]int, string, error) {
+	types, err := getOptionTypes(tx, productID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(types) == 0 {
+		return nil, "", fmt.Errorf("%w: buat tipe opsi produk terlebih dahulu", models.ErrInvalidVariant)
+	}
+
+	requested := make(map[string]string, len(options))
+	for name, value := range options {
+		requested[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	if len(requested) != len(types) {
+		return nil, "", fmt.Errorf("%w: options harus berisi tepat satu nilai untuk setiap tipe opsi (%d)", models.ErrInvalidVariant, len(types))
+	}
+
+	valueIDs := make([]int, 0, len(types))
+	for _, t := range types {
+		value, ok := requested[strings.ToLower(t.Name)]
+		if !ok {
+			return nil, "", fmt.Errorf("%w: nilai untuk tipe opsi %q wajib diisi", models.ErrInvalidVariant, t.Name)
+		}
+		found := false
+		for _, v := range t.Values {
+			if strings.EqualFold(v.Value, value) {
+				valueIDs = append(valueIDs, v.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, "", fmt.Errorf("%w: nilai %q tidak ada di tipe opsi %q", models.ErrInvalidVariant, value, t.Name)
+		}
+	}
+
+	// option_key adalah id nilai opsi yang diurutkan, dijaga unik per produk oleh database
+	sorted := append([]int(nil), valueIDs...)
+	sort.Ints(sorted)
+	parts := make([]string, len(sorted))
+	for i, id := range sorted {
+		parts[i] = strconv.Itoa(id)
+	}
+	key := strings.Join(parts, ",")
+
+	var exists bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1 AND option_key = $2 AND id <> $3)",
+		productID, key, excludeVariantID).Scan(&exists)
+	if err != nil {
+		return nil, "", err
+	}
+	if exists {
+		return nil, "", models.ErrDuplicateVariant
+	}
+
+	return valueIDs, key, nil
+}
+
+func requireOptionType(tx *sql.Tx, productID, optionTypeID int) error {
+	var exists bool
+	err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM product_option_types WHERE id = $1 AND product_id = $2)", optionTypeID, productID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return models.ErrOptionTypeNotFound
+	}
+	return nil
+}
+
+func requireNoVariantsYet(tx *sql.Tx, productID int) error {
+	var exists bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1)", productID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return models.ErrOptionTypeInUse
+	}
+	return nil
+}
+
+// variantNameSQL menghasilkan nama varian (nilai opsi digabung sesuai urutan tipe opsi) untuk baris varian beralias v
+const variantNameSQL = `COALESCE((SELECT string_agg(ov.value, ' / ' ORDER BY ot.position, ot.id)
+	FROM product_variant_options vo
+	JOIN product_option_values ov ON ov.id = vo.option_value_id
+	JOIN product_option_types ot ON ot.id = ov.option_type_id
+	WHERE vo.variant_id = v.id), '')`
+
+// lockStockTarget mengunci produk induk lalu variannya (urutan lock sama dengan checkout) dan mengembalikan
+// stok yang akan diubah: stok varian jika variantID > 0, selain itu stok produk. Produk bervarian wajib
+// menyebut variantID, dan varian harus milik produk tersebut.
+func lockStockTarget(tx *sql.Tx, productID, variantID int) (int, error) {
+	var stock int
+	var hasVariants bool
+	err := tx.QueryRow(`
+		SELECT stock, EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id)
+		FROM products p WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, productID).Scan(&stock, &hasVariants)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: id %d", models.ErrProductNotFound, productID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if variantID == 0 {
+		if hasVariants {
+			return 0, fmt.Errorf("%w: product id %d", models.ErrVariantRequired, productID)
+		}
+		return stock, nil
+	}
+
+	err = tx.QueryRow("SELECT stock FROM product_variants WHERE id = $1 AND product_id = $2 FOR UPDATE", variantID, productID).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: variant id %d", models.ErrVariantNotFound, variantID)
+	}
+	return stock, err
+}
+
+// applyStockDelta menambah stok varian (jika ada) dan stok produk induk sebesar delta agar stok produk
+// selalu sama dengan jumlah stok variannya. Mengembalikan stok produk setelah perubahan untuk balance_after ledger.
+// Target harus sudah dikunci dengan lockStockTarget.
+func applyStockDelta(tx *sql.Tx, productID, variantID, delta int) (int, error) {
+	if variantID != 0 {
+		if _, err := tx.Exec("UPDATE product_variants SET stock = stock + $1 WHERE id = $2", delta, variantID); err != nil {
+			return 0, err
+		}
+	}
+	var balance int
+	err := tx.QueryRow("UPDATE products SET stock = stock + $1, version = version + 1 WHERE id = $2 RETURNING stock", delta, productID).Scan(&balance)
+	return balance, err
+}
+
+// variantRef mengubah variantID (0 = tanpa varian) menjadi nilai nullable untuk kolom variant_id
+func variantRef(variantID int) *int {
+	if variantID == 0 {
+		return nil
+	}
+	return &variantID
+}
+
+// requireNoVariants menolak perubahan stok di level produk untuk produk bervarian
+func requireNoVariants(q rowQueryer, productID int) error {
+	var exists bool
+	if err := q.QueryRow("SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1)", productID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: produk id %d", models.ErrProductHasVariants, productID)
+	}
+	return nil
+}
+
+type rowQueryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func requireProduct(q rowQueryer, productID int) error {
+	var exists bool
+	if err := q.QueryRow("SELECT EXISTS (SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)", productID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return models.ErrProductNotFound
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"simple-crud/models"
+	"simple-crud/repository"
+)
+
+type CartService struct {
+	repo repository.CartRepository
+	ttl  time.Duration
+}
+
+func NewCartService(repo repository.CartRepository, ttl time.Duration) *CartService {
+	return &CartService{repo: repo, ttl: ttl}
+}
+
+func (s *CartService) GetAll(status string) ([]models.Cart, error) {
+	carts, err := s.repo.GetAll(status)
+	if err != nil {
+		return nil, err
+	}
+	for i := range carts {
+		carts[i].ExpiresAt = carts[i].UpdatedAt.Add(s.ttl)
+	}
+	return carts, nil
+}
+
+func (s *CartService) GetByID(id int) (*models.Cart, error) {
+	cart, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	cart.ExpiresAt = cart.UpdatedAt.Add(s.ttl)
+	return cart, nil
+}
+
+func (s *CartService) Create(req models.CreateCartRequest) (*models.Cart, error) {
+	id, err := s.repo.Create(strings.TrimSpace(req.Name))
+	if err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+func (s *CartService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func (s *CartService) AddItem(cartID int, req models.CartItemRequest) (*models.Cart, error) {
+	if err := s.repo.AddItem(cartID, req.ProductID, req.VariantID, req.Quantity); err != nil {
+		return nil, err
+	}
+	return s.GetByID(cartID)
+}
+
+func (s *CartService) UpdateItem(cartID, productID, variantID, quantity int) (*models.Cart, error) {
+	if err := s.repo.SetItemQuantity(cartID, productID, variantID, quantity); err != nil {
+		return nil, err
+	}
+	return s.GetByID(cartID)
+}
+
+func (s *CartService) RemoveItem(cartID, productID, variantID int) (*models.Cart, error) {
+	if err := s.repo.RemoveItem(cartID, productID, variantID); err != nil {
+		return nil, err
+	}
+	return s.GetByID(cartID)
+}
+
+func (s *CartService) Hold(cartID int, req models.HoldCartRequest) (*models.Cart, error) {
+	if err := s.repo.Hold(cartID, strings.TrimSpace(req.Name)); err != nil {
+		return nil, err
+	}
+	return s.GetByID(cartID)
+}
+
+func (s *CartService) Resume(cartID int) (*models.Cart, error) {
+	if err := s.repo.Resume(cartID); err != nil {
+		return nil, err
+	}
+	return s.GetByID(cartID)
+}
+
+// Checkout mengubah keranjang menjadi transaksi dengan logika checkout yang sama (promo, pajak, stok, pembayaran)
+func (s *CartService) Checkout(cartID int, req models.CartCheckoutRequest, actor string) (*models.Transaction, error) {
+	return s.repo.Checkout(cartID, req, actor)
+}
+
+// StartCleanup menjalankan goroutine yang menghapus keranjang yang tidak diubah lebih lama dari TTL
+// setiap interval. Keranjang yang masih dipakai diperpanjang otomatis karena setiap perubahan memperbarui updated_at.
+func (s *CartService) StartCleanup(interval time.Duration) {
+	if interval <= 0 || s.ttl <= 0 {
+		log.Println("cart cleanup disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			n, err := s.repo.DeleteExpired(s.ttl)
+			if err != nil {
+				log.Println("cart cleanup failed:", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("cart cleanup: %d keranjang kedaluwarsa dihapus", n)
+			}
+		}
+	}()
+}
@@ -1,16 +1,22 @@
 package service
 
 import (
+	"fmt"
+
 	model "simple-crud/models"
 	"simple-crud/repository"
+	"simple-crud/util"
 )
 
 type CategoriesService interface {
-	GetAll() ([]model.Category, error)
-	GetByID(id int) (*model.Category, error)
+	GetAll(includeDeleted bool) ([]model.Category, error)
+	GetTree(includeDeleted bool) ([]model.CategoryNode, error)
+	GetByID(id int, includeDeleted bool) (*model.Category, error)
 	Create(category model.Category) model.Category
-	Update(id int, category model.Category) error
-	Delete(id int) error
+	Update(id int, category model.Category, ifMatch model.IfMatch) (*model.Category, error)
+	Delete(id int, opts model.CategoryDeleteOptions, ifMatch model.IfMatch) (*model.CategoryDeleteResult, error)
+	Restore(id int) (*model.Category, error)
+	Patch(id int, patch []byte, ifMatch model.IfMatch) (*model.Category, error)
 }
 
 type CategoryService struct {
@@ -21,15 +27,27 @@ func NewCategoryService(repo repository.CategoryRepository) *CategoryService {
 	return &CategoryService{repo: repo}
 }
 
-func (s *CategoryService) GetAll() ([]model.Category, error) {
-	return s.repo.GetAll()
+func (s *CategoryService) GetAll(includeDeleted bool) ([]model.Category, error) {
+	return s.repo.GetAll(includeDeleted)
 }
 
-func (s *CategoryService) GetByID(id int) (*model.Category, error) {
-	return s.repo.GetByID(id)
+// GetTree mengembalikan seluruh kategori dalam bentuk pohon, setiap level urut nama
+func (s *CategoryService) GetTree(includeDeleted bool) ([]model.CategoryNode, error) {
+	categories, err := s.repo.GetAll(includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+	return model.BuildCategoryTree(categories), nil
+}
+
+func (s *CategoryService) GetByID(id int, includeDeleted bool) (*model.Category, error) {
+	return s.repo.GetByID(id, includeDeleted)
 }
 
 func (s *CategoryService) Create(category model.Category) (model.Category, error) {
+	if err := category.Validate(); err != nil {
+		return model.Category{}, err
+	}
 	c, err := s.repo.Create(category)
 	if err != nil {
 		return model.Category{}, err
@@ -37,10 +55,59 @@ func (s *CategoryService) Create(category model.Category) (model.Category, error
 	return *c, nil
 }
 
-func (s *CategoryService) Update(id int, category model.Category) error {
-	return s.repo.Update(id, category)
+// Update memvalidasi dan menimpa kategori lalu mengembalikan datanya dengan versi terbaru
+func (s *CategoryService) Update(id int, category model.Category, ifMatch model.IfMatch) (*model.Category, error) {
+	if err := category.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(id, category, ifMatch); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id, false)
 }
 
-func (s *CategoryService) Delete(id int) error {
-	return s.repo.Delete(id)
+// Patch menerapkan JSON Merge Patch pada kategori, memvalidasi hasilnya lalu menyimpan dengan versi yang dibaca di awal
+func (s *CategoryService) Patch(id int, patch []byte, ifMatch model.IfMatch) (*model.Category, error) {
+	current, err := s.repo.GetByID(id, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := ifMatch.Check(current.Version); err != nil {
+		return nil, err
+	}
+
+	var merged model.Category
+	if err := util.ApplyMergePatch(current, patch, &merged); err != nil {
+		return nil, err
+	}
+	if err := merged.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(id, merged, model.IfMatch{Versions: []int{current.Version}}); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id, false)
+}
+
+// Delete menghapus kategori; opts menentukan penanganan sub-kategori (default refuse) dan produk yang masih memakainya
+func (s *CategoryService) Delete(id int, opts model.CategoryDeleteOptions, ifMatch model.IfMatch) (*model.CategoryDeleteResult, error) {
+	if opts.Children == "" {
+		opts.Children = model.CategoryChildrenRefuse
+	}
+	if opts.Children != model.CategoryChildrenRefuse && opts.Children != model.CategoryChildrenReparent {
+		return nil, model.ErrInvalidCategoryDeleteMode
+	}
+	if opts.ReassignTo != nil && *opts.ReassignTo == id {
+		return nil, fmt.Errorf("%w: tidak bisa sama dengan kategori yang dihapus", model.ErrInvalidCategoryReassign)
+	}
+	return s.repo.Delete(id, opts, ifMatch)
+}
+
+// Restore mengembalikan kategori yang sudah dihapus lalu mengembalikan datanya
+func (s *CategoryService) Restore(id int) (*model.Category, error) {
+	if err := s.repo.Restore(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id, false)
 }
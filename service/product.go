@@ -1,16 +1,22 @@
 package service
 
 import (
+	"strings"
+
 	model "simple-crud/models"
 	"simple-crud/repository"
+	"simple-crud/util"
 )
 
 type ProductServices interface {
-	GetAll(name string) ([]model.Product, error)
-	GetByID(id int) (*model.Product, error)
-	Create(product *model.Product) (*model.Product, error)
-	Update(product *model.Product) (*model.Product, error)
-	Delete(id int) error
+	GetAll(filter model.ProductFilter) (*model.ProductPage, error)
+	GetByID(id int, includeDeleted bool) (*model.Product, error)
+	GetByBarcode(barcode string) (*model.Product, error)
+	Create(product *model.Product, actor string) (*model.Product, error)
+	Update(product *model.Product, ifMatch model.IfMatch, actor string) (*model.Product, error)
+	Delete(id int, ifMatch model.IfMatch) error
+	Restore(id int) (*model.Product, error)
+	Patch(id int, patch []byte, ifMatch model.IfMatch, actor string) (*model.Product, error)
 }
 
 type ProductService struct {
@@ -23,29 +29,80 @@ func NewProductService(repo repository.ProductRepository) *ProductService {
 	}
 }
 
-func (s *ProductService) GetAll(name string) ([]model.Product, error) {
-	return s.repo.GetAll(name)
+func (s *ProductService) GetAll(filter model.ProductFilter) (*model.ProductPage, error) {
+	return s.repo.GetAll(filter)
 }
 
-func (s *ProductService) GetByID(id int) (*model.Product, error) {
-	return s.repo.GetByID(id)
+func (s *ProductService) GetByID(id int, includeDeleted bool) (*model.Product, error) {
+	return s.repo.GetByID(id, includeDeleted)
 }
 
-func (s *ProductService) Create(product *model.Product) (*model.Product, error) {
-	created, err := s.repo.Create(product)
+// GetByBarcode mencari produk dari hasil scan; barcode dengan check digit salah langsung ditolak
+func (s *ProductService) GetByBarcode(barcode string) (*model.Product, error) {
+	barcode = strings.TrimSpace(barcode)
+	if err := model.ValidateBarcode(barcode); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByBarcode(barcode)
+}
+
+// Create memvalidasi produk dengan aturan yang sama seperti PUT dan PATCH lalu menyimpannya
+func (s *ProductService) Create(product *model.Product, actor string) (*model.Product, error) {
+	if err := product.Validate(); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.Create(product, actor)
 	if err != nil {
 		return nil, err
 	}
-	return s.repo.GetByID(created.ID)
+	return s.repo.GetByID(created.ID, false)
 }
 
-func (s *ProductService) Update(product *model.Product) (*model.Product, error) {
-	if err := s.repo.Update(product); err != nil {
+// Update memvalidasi lalu menimpa seluruh field produk yang bisa diubah
+func (s *ProductService) Update(product *model.Product, ifMatch model.IfMatch, actor string) (*model.Product, error) {
+	if err := product.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(product, ifMatch, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(product.ID, false)
+}
+
+// Patch menerapkan JSON Merge Patch pada produk: field yang tidak dikirim tetap, hasil gabungan divalidasi ulang
+// lalu disimpan lewat Update dengan versi yang dibaca di awal agar perubahan lain di antaranya tidak tertimpa
+func (s *ProductService) Patch(id int, patch []byte, ifMatch model.IfMatch, actor string) (*model.Product, error) {
+	current, err := s.repo.GetByID(id, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := ifMatch.Check(current.Version); err != nil {
+		return nil, err
+	}
+
+	var merged model.Product
+	if err := util.ApplyMergePatch(current, patch, &merged); err != nil {
+		return nil, err
+	}
+	merged.ID = id
+	if err := merged.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&merged, model.IfMatch{Versions: []int{current.Version}}, actor); err != nil {
 		return nil, err
 	}
-	return s.repo.GetByID(product.ID)
+	return s.repo.GetByID(id, false)
 }
 
-func (s *ProductService) Delete(id int) error {
-	return s.repo.Delete(id)
+func (s *ProductService) Delete(id int, ifMatch model.IfMatch) error {
+	return s.repo.Delete(id, ifMatch)
+}
+
+// Restore mengembalikan produk yang sudah dihapus lalu mengembalikan datanya
+func (s *ProductService) Restore(id int) (*model.Product, error) {
+	if err := s.repo.Restore(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id, false)
 }
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"strings"
+
+	"simple-crud/models"
+	"simple-crud/repository"
+)
+
+type PromotionService struct {
+	repo repository.PromotionRepository
+}
+
+func NewPromotionService(repo repository.PromotionRepository) *PromotionService {
+	return &PromotionService{repo: repo}
+}
+
+func (s *PromotionService) GetAll(activeOnly bool) ([]models.Promotion, error) {
+	if activeOnly {
+		return s.repo.GetActive()
+	}
+	return s.repo.GetAll()
+}
+
+func (s *PromotionService) GetByID(id int) (*models.Promotion, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *PromotionService) Create(p models.Promotion) (*models.Promotion, error) {
+	p = normalizePromotion(p)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(p)
+}
+
+func (s *PromotionService) Update(id int, p models.Promotion) (*models.Promotion, error) {
+	p = normalizePromotion(p)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(id, p); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PromotionService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// normalizePromotion menyeragamkan kode voucher (huruf besar, tanpa spasi) dan mata uang nominal
+func normalizePromotion(p models.Promotion) models.Promotion {
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	p.Amount = models.NewMoney(p.Amount.Amount)
+	p.MinSubtotal = models.NewMoney(p.MinSubtotal.Amount)
+	return p
+}
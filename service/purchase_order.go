@@ -0,0 +1,71 @@
+package service
+
+import (
+	"strings"
+
+	"simple-crud/models"
+	"simple-crud/repository"
+)
+
+type PurchaseOrderService struct {
+	repo repository.PurchaseOrderRepository
+}
+
+func NewPurchaseOrderService(repo repository.PurchaseOrderRepository) *PurchaseOrderService {
+	return &PurchaseOrderService{repo: repo}
+}
+
+func (s *PurchaseOrderService) GetAll(filter models.PurchaseOrderFilter) ([]models.PurchaseOrder, error) {
+	return s.repo.GetAll(filter)
+}
+
+func (s *PurchaseOrderService) GetByID(id int) (*models.PurchaseOrder, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Create(req models.PurchaseOrderRequest, actor string) (*models.PurchaseOrder, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	id, err := s.repo.Create(req, actor)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Update(id int, req models.PurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(id, req); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Send(id int) (*models.PurchaseOrder, error) {
+	if err := s.repo.Send(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *PurchaseOrderService) Cancel(id int) (*models.PurchaseOrder, error) {
+	if err := s.repo.Cancel(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Receive mencatat barang yang datang dan menambah stok produk
+func (s *PurchaseOrderService) Receive(id int, req models.ReceiveRequest, actor string) (*models.PurchaseOrder, error) {
+	req.Note = strings.TrimSpace(req.Note)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Receive(id, req, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
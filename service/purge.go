@@ -0,0 +1,42 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"simple-crud/repository"
+)
+
+type PurgeService struct {
+	repo      repository.PurgeRepository
+	retention time.Duration
+}
+
+func NewPurgeService(repo repository.PurgeRepository, retention time.Duration) *PurgeService {
+	return &PurgeService{repo: repo, retention: retention}
+}
+
+// StartPurge menjalankan goroutine yang setiap interval menghapus permanen produk dan kategori
+// yang sudah di-soft delete lebih lama dari masa retensi. Sebelum itu data masih bisa di-restore.
+func (s *PurgeService) StartPurge(interval time.Duration) {
+	if interval <= 0 || s.retention <= 0 {
+		log.Println("purge soft delete disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			products, categories, err := s.repo.PurgeDeleted(s.retention)
+			if err != nil {
+				log.Println("purge soft delete failed:", err)
+				continue
+			}
+			if products > 0 || categories > 0 {
+				log.Printf("purge soft delete: %d produk dan %d kategori dihapus permanen", products, categories)
+			}
+		}
+	}()
+}
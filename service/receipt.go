@@ -0,0 +1,267 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"simple-crud/models"
+	"simple-crud/repository"
+)
+
+//go:embed templates/receipt.*.tmpl
+var defaultReceiptTemplates embed.FS
+
+// receiptTemplate adalah template text/template maupun html/template yang sudah di-parse
+type receiptTemplate interface {
+	Execute(w io.Writer, data any) error
+}
+
+type ReceiptService struct {
+	repo       repository.TransactionRepository
+	store      models.ReceiptStore
+	paperWidth int
+	templates  map[string]receiptTemplate
+}
+
+// NewReceiptService mem-parse template struk untuk setiap format. File receipt.<format>.tmpl
+// di templatesDir (jika ada) menggantikan template bawaan untuk format tersebut.
+func NewReceiptService(repo repository.TransactionRepository, store models.ReceiptStore, templatesDir string, paperWidth int) (*ReceiptService, error) {
+	if _, ok := receiptLineWidth(paperWidth); !ok {
+		return nil, models.ErrInvalidPaperWidth
+	}
+
+	s := &ReceiptService{
+		repo:       repo,
+		store:      store,
+		paperWidth: paperWidth,
+		templates:  make(map[string]receiptTemplate),
+	}
+
+	for _, format := range []string{models.ReceiptFormatText, models.ReceiptFormatHTML, models.ReceiptFormatESCPOS} {
+		name := "receipt." + format + ".tmpl"
+		src, err := readReceiptTemplate(templatesDir, name)
+		if err != nil {
+			return nil, err
+		}
+
+		var tmpl receiptTemplate
+		if format == models.ReceiptFormatHTML {
+			tmpl, err = htmltemplate.New(name).Funcs(receiptFuncs).Parse(src)
+		} else {
+			tmpl, err = template.New(name).Funcs(receiptFuncs).Parse(src)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse template %s: %w", name, err)
+		}
+		s.templates[format] = tmpl
+	}
+
+	return s, nil
+}
+
+func readReceiptTemplate(dir, name string) (string, error) {
+	if dir != "" {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return string(b), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
+	b, err := defaultReceiptTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Render membuat struk transaksi dalam format txt, html, atau escpos.
+// paperWidth 0 berarti memakai lebar kertas dari konfigurasi.
+func (s *ReceiptService) Render(transactionID int, format string, paperWidth int) ([]byte, error) {
+	tmpl, ok := s.templates[format]
+	if !ok {
+		return nil, models.ErrUnsupportedReceiptFormat
+	}
+
+	if paperWidth == 0 {
+		paperWidth = s.paperWidth
+	}
+	width, ok := receiptLineWidth(paperWidth)
+	if !ok {
+		return nil, models.ErrInvalidPaperWidth
+	}
+
+	transaction, err := s.repo.GetTransactionByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := models.ReceiptData{
+		Store:       s.store,
+		Transaction: transaction,
+		Subtotal:    models.NewMoney(0),
+		PaperWidth:  paperWidth,
+		Width:       width,
+		PrintedAt:   time.Now(),
+	}
+
+	// Pajak dijumlahkan per tarif sesuai snapshot di setiap baris
+	taxIndex := make(map[string]int)
+	for _, d := range transaction.Details {
+		data.Subtotal = data.Subtotal.Add(d.Subtotal)
+		if d.TaxName == "" {
+			continue
+		}
+		key := d.TaxName + "|" + d.TaxMode
+		i, ok := taxIndex[key]
+		if !ok {
+			i = len(data.Taxes)
+			taxIndex[key] = i
+			data.Taxes = append(data.Taxes, models.ReceiptTax{
+				Name:      d.TaxName,
+				Mode:      d.TaxMode,
+				Amount:    models.NewMoney(0),
+				Inclusive: d.TaxMode == models.TaxModeInclusive,
+			})
+		}
+		data.Taxes[i].Amount = data.Taxes[i].Amount.Add(d.TaxAmount)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	// Printer thermal memakai code page 8-bit; karakter non-ASCII diganti agar tidak tercetak rusak
+	if format == models.ReceiptFormatESCPOS {
+		return toASCII(buf.Bytes()), nil
+	}
+	return buf.Bytes(), nil
+}
+
+// receiptLineWidth mengembalikan jumlah karakter per baris (font A) untuk lebar kertas thermal
+func receiptLineWidth(paperWidth int) (int, bool) {
+	switch paperWidth {
+	case 58:
+		return 32, true
+	case 80:
+		return 48, true
+	default:
+		return 0, false
+	}
+}
+
+func toASCII(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		} else {
+			out = append(out, '?')
+		}
+		b = b[size:]
+	}
+	return out
+}
+
+// ============================
+// TEMPLATE FUNCS
+// ============================
+
+var receiptFuncs = map[string]any{
+	"money":  formatRupiah,
+	"row":    receiptRow,
+	"center": receiptCenter,
+	"line":   func(width int, char string) string { return strings.Repeat(char, width) },
+	"date":   func(t time.Time) string { return t.Format("02/01/2006 15:04") },
+	"upper":  strings.ToUpper,
+
+	// Perintah ESC/POS
+	"escInit": func() string { return "\x1b@" },
+	"escBold": func(on bool) string {
+		if on {
+			return "\x1bE\x01"
+		}
+		return "\x1bE\x00"
+	},
+	"escAlign": func(align string) string {
+		switch align {
+		case "center":
+			return "\x1ba\x01"
+		case "right":
+			return "\x1ba\x02"
+		default:
+			return "\x1ba\x00"
+		}
+	},
+	"escDoubleHeight": func(on bool) string {
+		if on {
+			return "\x1d!\x01"
+		}
+		return "\x1d!\x00"
+	},
+	"escFeed": func(lines int) string { return "\x1bd" + string(rune(lines&0x7f)) },
+	"escCut":  func() string { return "\x1dVB\x00" },
+}
+
+// formatRupiah menulis Money dengan pemisah ribuan titik, mis. 12.500 atau 12.500,50
+func formatRupiah(m models.Money) string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprintf("%d", amount/models.MinorUnitScale)
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+
+	if cents := amount % models.MinorUnitScale; cents != 0 {
+		return fmt.Sprintf("%s%s,%02d", sign, b.String(), cents)
+	}
+	return sign + b.String()
+}
+
+// receiptRow menulis left rata kiri dan right rata kanan dalam satu baris selebar width.
+// Jika tidak muat, left dipotong.
+func receiptRow(width int, left, right string) string {
+	space := width - utf8.RuneCountInString(right) - 1
+	if space < 0 {
+		space = 0
+	}
+	if utf8.RuneCountInString(left) > space {
+		left = string([]rune(left)[:space])
+	}
+	pad := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if pad < 1 {
+		pad = 1
+	}
+	return left + strings.Repeat(" ", pad) + right
+}
+
+// receiptCenter menengahkan s dalam baris selebar width
+func receiptCenter(width int, s string) string {
+	n := utf8.RuneCountInString(s)
+	if n >= width {
+		return s
+	}
+	return strings.Repeat(" ", (width-n)/2) + s
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"simple-crud/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// readSpreadsheet membaca seluruh sel file csv atau xlsx (sheet pertama) sebagai teks.
+// CSV boleh memakai pemisah koma atau titik koma (default Excel berbahasa Indonesia).
+func readSpreadsheet(r io.Reader, format string) ([][]string, error) {
+	switch format {
+	case models.SpreadsheetFormatCSV:
+		br := bufio.NewReader(r)
+		firstLine, _ := br.Peek(4096)
+		if i := bytes.IndexByte(firstLine, '\n'); i >= 0 {
+			firstLine = firstLine[:i]
+		}
+
+		reader := csv.NewReader(br)
+		if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+			reader.Comma = ';'
+		}
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidImport, err)
+		}
+		return rows, nil
+
+	case models.SpreadsheetFormatXLSX:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidImport, err)
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("%w: file tidak memiliki sheet", models.ErrInvalidImport)
+		}
+		// Nilai mentah agar barcode/angka tidak berubah menjadi format tampilan (mis. 8.99E+12)
+		rows, err := f.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidImport, err)
+		}
+		return rows, nil
+
+	default:
+		return nil, models.ErrUnsupportedSpreadsheetFormat
+	}
+}
+
+// spreadsheetWriter menulis baris export satu per satu ke csv atau xlsx
+type spreadsheetWriter interface {
+	WriteRow(values []string) error
+	// Close menyelesaikan file; untuk xlsx seluruh workbook baru ditulis ke writer pada tahap ini
+	Close() error
+}
+
+// newSpreadsheetWriter membuat writer untuk format csv atau xlsx; sheet dipakai sebagai nama sheet xlsx
+func newSpreadsheetWriter(w io.Writer, format, sheet string) (spreadsheetWriter, error) {
+	switch format {
+	case models.SpreadsheetFormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+
+	case models.SpreadsheetFormatXLSX:
+		f := excelize.NewFile()
+		if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+			f.Close()
+			return nil, err
+		}
+		sw, err := f.NewStreamWriter(sheet)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &xlsxWriter{out: w, file: f, stream: sw}, nil
+
+	default:
+		return nil, models.ErrUnsupportedSpreadsheetFormat
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) WriteRow(values []string) error {
+	return c.w.Write(values)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func (x *xlsxWriter) WriteRow(values []string) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	// Semua sel ditulis sebagai teks supaya SKU/barcode dengan nol di depan tidak berubah
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		row[i] = v
+	}
+	return x.stream.SetRow(cell, row)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"simple-crud/models"
+	"simple-crud/repository"
+)
+
+type StockAlertService struct {
+	repo         repository.StockAlertRepository
+	velocityDays int
+	coverDays    int
+}
+
+// NewStockAlertService: velocityDays adalah periode penjualan untuk menghitung velocity,
+// coverDays adalah jumlah hari penjualan yang harus tertutup oleh saran pesan ulang
+func NewStockAlertService(repo repository.StockAlertRepository, velocityDays, coverDays int) *StockAlertService {
+	return &StockAlertService{repo: repo, velocityDays: velocityDays, coverDays: coverDays}
+}
+
+// GetLowStock mengembalikan produk dengan stok menipis; nilai 0 memakai default dari konfigurasi
+func (s *StockAlertService) GetLowStock(velocityDays, coverDays int) ([]models.LowStockProduct, error) {
+	if velocityDays <= 0 {
+		velocityDays = s.velocityDays
+	}
+	if coverDays <= 0 {
+		coverDays = s.coverDays
+	}
+
+	products, err := s.repo.GetLowStock(velocityDays)
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		products[i].SuggestReorder(velocityDays, coverDays)
+	}
+	return products, nil
+}
+
+// GetAfter mengembalikan alert setelah afterID untuk konsumen polling
+func (s *StockAlertService) GetAfter(afterID, limit int) ([]models.StockAlert, error) {
+	return s.repo.GetAfter(afterID, limit)
+}
+
+// LatestID dipakai stream SSE yang baru terhubung agar hanya menerima alert baru
+func (s *StockAlertService) LatestID() (int, error) {
+	return s.repo.LatestID()
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"strings"
+
+	"simple-crud/models"
+	"simple-crud/repository"
+)
+
+type StockMovementService struct {
+	repo repository.StockMovementRepository
+}
+
+func NewStockMovementService(repo repository.StockMovementRepository) *StockMovementService {
+	return &StockMovementService{repo: repo}
+}
+
+// GetByProduct mengembalikan ledger stok satu produk, ErrProductNotFound jika produk tidak ada
+func (s *StockMovementService) GetByProduct(productID int, filter models.StockMovementFilter) ([]models.StockMovement, int, error) {
+	exists, err := s.repo.ProductExists(productID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !exists {
+		return nil, 0, models.ErrProductNotFound
+	}
+	return s.repo.GetByProduct(productID, filter)
+}
+
+// Adjust mengoreksi stok produk tanpa menyentuh nama, harga atau kategori
+func (s *StockMovementService) Adjust(productID int, req models.StockAdjustmentRequest, actor string) (*models.StockMovement, error) {
+	req.ReasonCode = strings.TrimSpace(req.ReasonCode)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Adjust(productID, req.VariantID, req.Quantity, req.LedgerNote(), actor)
+}
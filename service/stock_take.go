@@ -0,0 +1,56 @@
+package service
+
+import (
+	"strings"
+
+	"simple-crud/models"
+	"simple-crud/repository"
+)
+
+type StockTakeService struct {
+	repo repository.StockTakeRepository
+}
+
+func NewStockTakeService(repo repository.StockTakeRepository) *StockTakeService {
+	return &StockTakeService{repo: repo}
+}
+
+func (s *StockTakeService) GetAll(status string) ([]models.StockTake, error) {
+	return s.repo.GetAll(status)
+}
+
+func (s *StockTakeService) GetByID(id int) (*models.StockTake, error) {
+	return s.repo.GetByID(id)
+}
+
+// Start membuka sesi stock-take baru
+func (s *StockTakeService) Start(req models.CreateStockTakeRequest, actor string) (*models.StockTake, error) {
+	id, err := s.repo.Create(strings.TrimSpace(req.Note), actor)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// SubmitCounts menyimpan hasil hitung lalu mengembalikan laporan selisih terbaru
+func (s *StockTakeService) SubmitCounts(id int, req models.StockTakeCountRequest) (*models.StockTake, error) {
+	if err := s.repo.SaveCounts(id, req.Items); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Commit memposting seluruh selisih ke stok produk sekaligus
+func (s *StockTakeService) Commit(id int, actor string) (*models.StockTake, error) {
+	if err := s.repo.Commit(id, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *StockTakeService) Cancel(id int) (*models.StockTake, error) {
+	if err := s.repo.Cancel(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
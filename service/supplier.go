@@ -0,0 +1,47 @@
+package service
+
+import (
+	"strings"
+
+	"simple-crud/models"
+	"simple-crud/repository"
+)
+
+type SupplierService struct {
+	repo repository.SupplierRepository
+}
+
+func NewSupplierService(repo repository.SupplierRepository) *SupplierService {
+	return &SupplierService{repo: repo}
+}
+
+func (s *SupplierService) GetAll() ([]models.Supplier, error) {
+	return s.repo.GetAll()
+}
+
+func (s *SupplierService) GetByID(id int) (*models.Supplier, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *SupplierService) Create(sup models.Supplier) (*models.Supplier, error) {
+	sup.Name = strings.TrimSpace(sup.Name)
+	if err := sup.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(sup)
+}
+
+func (s *SupplierService) Update(id int, sup models.Supplier) (*models.Supplier, error) {
+	sup.Name = strings.TrimSpace(sup.Name)
+	if err := sup.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(id, sup); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *SupplierService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
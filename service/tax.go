@@ -0,0 +1,43 @@
+package service
+
+import (
+	"simple-crud/models"
+	"simple-crud/repository"
+)
+
+type TaxRateService struct {
+	repo repository.TaxRateRepository
+}
+
+func NewTaxRateService(repo repository.TaxRateRepository) *TaxRateService {
+	return &TaxRateService{repo: repo}
+}
+
+func (s *TaxRateService) GetAll() ([]models.TaxRate, error) {
+	return s.repo.GetAll()
+}
+
+func (s *TaxRateService) GetByID(id int) (*models.TaxRate, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *TaxRateService) Create(t models.TaxRate) (*models.TaxRate, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(t)
+}
+
+func (s *TaxRateService) Update(id int, t models.TaxRate) (*models.TaxRate, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(id, t); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *TaxRateService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
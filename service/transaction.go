@@ -1,80 +1,222 @@
 package service
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
 	"simple-crud/models"
 	"simple-crud/repository"
 	"simple-crud/util"
 )
 
 type TransactionService struct {
-	repo repository.TransactionRepository
+	repo            repository.TransactionRepository
+	idempotencyRepo repository.IdempotencyRepository
+	idempotencyTTL  time.Duration
+}
+
+func NewTransactionService(repo repository.TransactionRepository, idempotencyRepo repository.IdempotencyRepository, idempotencyTTL time.Duration) *TransactionService {
+	return &TransactionService{
+		repo:            repo,
+		idempotencyRepo: idempotencyRepo,
+		idempotencyTTL:  idempotencyTTL,
+	}
 }
 
-func NewTransactionService(repo repository.TransactionRepository) *TransactionService {
-	return &TransactionService{repo: repo}
+// Checkout membuat transaksi dari item keranjang. Jika useLock bernilai true, baris produk
+// di-lock (SELECT ... FOR UPDATE) selama transaksi sehingga dua kasir tidak bisa menjual stok yang sama.
+// actor dicatat di ledger stok.
+func (s *TransactionService) Checkout(req models.CheckoutRequest, useLock bool, actor string) (*models.Transaction, error) {
+	return s.repo.CreateTransaction(req, useLock, actor)
+}
+
+// CheckoutIdempotent menjalankan Checkout satu kali untuk setiap Idempotency-Key.
+// Request ulang dengan key dan body yang sama mendapatkan transaksi semula (replayed = true)
+// tanpa membuat transaksi baru maupun mengurangi stok lagi.
+func (s *TransactionService) CheckoutIdempotent(key string, req models.CheckoutRequest, useLock bool, actor string) (*models.Transaction, bool, error) {
+	requestHash, err := hashCheckoutRequest(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, reserved, err := s.idempotencyRepo.Reserve(key, requestHash, s.idempotencyTTL)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !reserved {
+		if existing.RequestHash != requestHash {
+			return nil, false, models.ErrIdempotencyKeyMismatch
+		}
+		if existing.ResponseBody == nil {
+			// Response gagal disimpan setelah commit, tetapi transaksinya tercatat: bangun ulang dari database
+			if existing.TransactionID != nil {
+				transaction, err := s.repo.GetTransactionByID(*existing.TransactionID)
+				if err != nil {
+					return nil, false, err
+				}
+				return transaction, true, nil
+			}
+			return nil, false, models.ErrIdempotencyKeyInProgress
+		}
+
+		var transaction models.Transaction
+		if err := json.Unmarshal(existing.ResponseBody, &transaction); err != nil {
+			return nil, false, err
+		}
+		return &transaction, true, nil
+	}
+
+	transaction, err := s.Checkout(req, useLock, actor)
+	if err != nil {
+		// Checkout gagal sehingga tidak ada yang perlu di-replay, key dilepas agar bisa dicoba lagi
+		if releaseErr := s.idempotencyRepo.Release(key); releaseErr != nil {
+			log.Println("Failed to release idempotency key:", releaseErr)
+		}
+		return nil, false, err
+	}
+
+	responseBody, err := json.Marshal(transaction)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Transaksi sudah ter-commit, jadi kegagalan menyimpan response tidak boleh menggagalkan request
+	s.completeIdempotencyKey(key, transaction.ID, responseBody)
+
+	return transaction, false, nil
 }
 
-func (s *TransactionService) Checkout(items []models.CheckoutItem, useLock bool) (*models.Transaction, error) {
-	return s.repo.CreateTransaction(items)
+// idempotencyCompleteAttempts adalah jumlah percobaan menyimpan response sebelum jatuh ke SetTransaction
+const idempotencyCompleteAttempts = 3
+
+// completeIdempotencyKey menyimpan response dengan beberapa kali percobaan. Jika tetap gagal, minimal
+// transaction_id disimpan agar retry client di-replay dari transaksinya, bukan tertahan 409 sampai key kedaluwarsa.
+func (s *TransactionService) completeIdempotencyKey(key string, transactionID int, responseBody []byte) {
+	var err error
+	for attempt := 1; attempt <= idempotencyCompleteAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * 100 * time.Millisecond)
+		}
+		if err = s.idempotencyRepo.Complete(key, transactionID, responseBody); err == nil {
+			return
+		}
+	}
+	log.Println("Failed to store idempotency response:", err)
+
+	if err := s.idempotencyRepo.SetTransaction(key, transactionID); err != nil {
+		log.Println("Failed to store idempotency transaction id:", err)
+	}
 }
 
-func (s *TransactionService) GetSalesSummary() (*util.SalesSummary, error) {
-	totalRevenue, totalTransaksi, err := s.repo.GetSalesSummary()
+// hashCheckoutRequest menghasilkan SHA-256 dari request checkout untuk mendeteksi body yang berbeda
+func hashCheckoutRequest(req models.CheckoutRequest) (string, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// GetTransactions mengembalikan daftar transaksi sesuai filter beserta total data
+func (s *TransactionService) GetTransactions(filter models.TransactionFilter) ([]models.Transaction, int, error) {
+	return s.repo.GetTransactions(filter)
+}
+
+func (s *TransactionService) GetTransactionByID(id int) (*models.Transaction, error) {
+	return s.repo.GetTransactionByID(id)
+}
+
+// VoidTransaction membatalkan transaksi hari ini secara penuh dan mengembalikan stok
+func (s *TransactionService) VoidTransaction(id int, req models.VoidRequest, actor string) (*models.Refund, error) {
+	return s.repo.VoidTransaction(id, req.Reason, req.Method, actor)
+}
+
+// RefundTransaction me-refund sebagian item transaksi dan mengembalikan stok
+func (s *TransactionService) RefundTransaction(id int, req models.RefundRequest, actor string) (*models.Refund, error) {
+	return s.repo.RefundTransaction(id, req.Reason, req.Method, req.Items, actor)
+}
+
+// GetSalesSummary menampilkan ringkasan hari ini; categoryID > 0 membatasi ke kategori beserta sub-kategorinya
+func (s *TransactionService) GetSalesSummary(categoryID int) (*util.SalesSummary, error) {
+	totals, err := s.repo.GetSalesSummary(categoryID)
 	if err != nil {
 		return nil, err
 	}
-	topSellingProduct, err := s.repo.GetTopSellingProduct()
+	topSellingProduct, err := s.repo.GetTopSellingProduct(categoryID)
 	if err != nil {
 		return nil, err
 	}
-
-	var summary util.SalesSummary
-	summary.TotalRevenue = totalRevenue
-	summary.TotalTransaksi = totalTransaksi
-	summary.ProdukTerlaris = util.ProdukTerlaris{
-		Nama:       topSellingProduct.Name,
-		QtyTerjual: topSellingProduct.QtySold,
+	profit, err := s.repo.GetProfitReport(categoryID)
+	if err != nil {
+		return nil, err
 	}
 
-	return &summary, nil
+	return newSalesSummary(totals, topSellingProduct, profit), nil
 }
 
 // Expose top selling product for handler usage
-func (s *TransactionService) GetTopSellingProduct() (*models.TopSellingProduct, error) {
-	return s.repo.GetTopSellingProduct()
+func (s *TransactionService) GetTopSellingProduct(categoryID int) (*models.TopSellingProduct, error) {
+	return s.repo.GetTopSellingProduct(categoryID)
 }
 
 // GetSalesSummaryByRange menerima start_date dan end_date (format: YYYY-MM-DD) untuk menampilkan ringkasan pada rentang tanggal.
 // Jika salah satu parameter kosong, gunakan perhitungan "hari ini" dengan GetSalesSummary().
-func (s *TransactionService) GetSalesSummaryByRange(startDate, endDate string) (*util.SalesSummary, error) {
+func (s *TransactionService) GetSalesSummaryByRange(startDate, endDate string, categoryID int) (*util.SalesSummary, error) {
 	if startDate == "" || endDate == "" {
-		return s.GetSalesSummary()
+		return s.GetSalesSummary(categoryID)
 	}
 
-	totalRevenue, totalTransaksi, err := s.repo.GetSalesSummaryByRange(startDate, endDate)
+	totals, err := s.repo.GetSalesSummaryByRange(startDate, endDate, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	topSellingProduct, err := s.repo.GetTopSellingProductByRange(startDate, endDate, categoryID)
 	if err != nil {
 		return nil, err
 	}
-	topSellingProduct, err := s.repo.GetTopSellingProductByRange(startDate, endDate)
+	profit, err := s.repo.GetProfitReportByRange(startDate, endDate, categoryID)
 	if err != nil {
 		return nil, err
 	}
 
+	return newSalesSummary(totals, topSellingProduct, profit), nil
+}
+
+func newSalesSummary(totals *models.SalesTotals, topSellingProduct *models.TopSellingProduct, profit *models.ProfitReport) *util.SalesSummary {
 	return &util.SalesSummary{
-		TotalRevenue:   totalRevenue,
-		TotalTransaksi: totalTransaksi,
+		TotalRevenue:   totals.NetRevenue,
+		GrossRevenue:   totals.GrossRevenue,
+		TotalDiscount:  totals.TotalDiscount,
+		TotalRefund:    totals.TotalRefund,
+		TotalTransaksi: totals.TotalTransaksi,
+		Pembayaran:     totals.Payments,
+		Profit:         *profit,
 		ProdukTerlaris: util.ProdukTerlaris{
 			Nama:       topSellingProduct.Name,
 			QtyTerjual: topSellingProduct.QtySold,
 		},
-	}, nil
+	}
 }
 
 // GetTopSellingProductByRange mengembalikan produk terlaris pada rentang tanggal.
 // Jika salah satu parameter kosong, fallback ke produk terlaris hari ini.
-func (s *TransactionService) GetTopSellingProductByRange(startDate, endDate string) (*models.TopSellingProduct, error) {
+func (s *TransactionService) GetTopSellingProductByRange(startDate, endDate string, categoryID int) (*models.TopSellingProduct, error) {
+	if startDate == "" || endDate == "" {
+		return s.GetTopSellingProduct(categoryID)
+	}
+	return s.repo.GetTopSellingProductByRange(startDate, endDate, categoryID)
+}
+
+// GetTaxReport mengembalikan laporan pajak per tarif; tanpa rentang tanggal dipakai hari ini
+func (s *TransactionService) GetTaxReport(startDate, endDate string) ([]models.TaxReportLine, error) {
 	if startDate == "" || endDate == "" {
-		return s.GetTopSellingProduct()
+		today := time.Now().Format("2006-01-02")
+		startDate, endDate = today, today
 	}
-	return s.repo.GetTopSellingProductByRange(startDate, endDate)
+	return s.repo.GetTaxReport(startDate, endDate)
 }
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"simple-crud/models"
+	"simple-crud/repository"
+)
+
+type VariantService struct {
+	repo repository.VariantRepository
+}
+
+func NewVariantService(repo repository.VariantRepository) *VariantService {
+	return &VariantService{repo: repo}
+}
+
+func (s *VariantService) GetOptionTypes(productID int) ([]models.ProductOptionType, error) {
+	return s.repo.GetOptionTypes(productID)
+}
+
+// CreateOptionType membuat tipe opsi lalu mengembalikan seluruh tipe opsi produk
+func (s *VariantService) CreateOptionType(productID int, req models.OptionTypeRequest) ([]models.ProductOptionType, error) {
+	if err := req.Validate(true); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.CreateOptionType(productID, req); err != nil {
+		return nil, err
+	}
+	return s.repo.GetOptionTypes(productID)
+}
+
+func (s *VariantService) AddOptionValues(productID, optionTypeID int, req models.OptionTypeRequest) ([]models.ProductOptionType, error) {
+	if err := req.Validate(false); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddOptionValues(productID, optionTypeID, req.Values); err != nil {
+		return nil, err
+	}
+	return s.repo.GetOptionTypes(productID)
+}
+
+func (s *VariantService) DeleteOptionType(productID, optionTypeID int) error {
+	return s.repo.DeleteOptionType(productID, optionTypeID)
+}
+
+func (s *VariantService) GetVariants(productID int) ([]models.ProductVariant, error) {
+	return s.repo.GetVariants(productID)
+}
+
+func (s *VariantService) GetVariant(productID, variantID int) (*models.ProductVariant, error) {
+	return s.repo.GetVariant(productID, variantID)
+}
+
+func (s *VariantService) CreateVariant(productID int, req models.VariantRequest, actor string) (*models.ProductVariant, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	id, err := s.repo.CreateVariant(productID, req, actor)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetVariant(productID, id)
+}
+
+func (s *VariantService) UpdateVariant(productID, variantID int, req models.VariantRequest, actor string) (*models.ProductVariant, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateVariant(productID, variantID, req, actor); err != nil {
+		return nil, err
+	}
+	return s.repo.GetVariant(productID, variantID)
+}
+
+func (s *VariantService) DeleteVariant(productID, variantID int, actor string) error {
+	return s.repo.DeleteVariant(productID, variantID, actor)
+}
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidMergePatch dikembalikan jika body PATCH bukan object JSON atau hasilnya memuat field yang tidak dikenal
+var ErrInvalidMergePatch = errors.New("merge patch tidak valid")
+
+// MergePatch menerapkan JSON Merge Patch (RFC 7396) pada dokumen target: field bernilai null dihapus,
+// object digabung secara rekursif, dan nilai lain (termasuk array) menggantikan nilai lama.
+// Angka dibaca sebagai json.Number agar nilai uang tidak berubah karena float.
+func MergePatch(target, patch []byte) ([]byte, error) {
+	p, err := decodeJSONNumber(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMergePatch, err)
+	}
+	if _, ok := p.(map[string]any); !ok {
+		return nil, fmt.Errorf("%w: body harus berupa object JSON", ErrInvalidMergePatch)
+	}
+
+	t, err := decodeJSONNumber(target)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(t, p))
+}
+
+// ApplyMergePatch menerapkan patch pada representasi JSON current lalu men-decode hasilnya ke dst.
+// Field yang tidak dikenal ditolak agar salah ketik nama field tidak diam-diam diabaikan.
+func ApplyMergePatch(current any, patch []byte, dst any) error {
+	target, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	merged, err := MergePatch(target, patch)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMergePatch, err)
+	}
+	return nil
+}
+
+func mergePatch(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = make(map[string]any, len(patchObj))
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
+
+func decodeJSONNumber(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	// Sisa data setelah dokumen pertama berarti body bukan satu dokumen JSON
+	if dec.More() {
+		return nil, errors.New("data tambahan setelah dokumen JSON")
+	}
+	return v, nil
+}
@@ -1,8 +1,26 @@
 package util
 
+import (
+	"time"
+
+	"simple-crud/models"
+)
+
 type JSONResponse struct {
-	Message string `json:"message"`
-	Data    any    `json:"data"`
+	Message string      `json:"message"`
+	Data    any         `json:"data"`
+	Meta    *Pagination `json:"meta,omitempty"`
+}
+
+type Pagination struct {
+	Page  int `json:"page,omitempty"` // kosong pada pagination berbasis cursor
+	Limit int `json:"limit"`
+	Total int `json:"total"`
+	// Cursor dan link halaman berikutnya/sebelumnya; hanya diisi oleh endpoint yang mendukungnya
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
 }
 
 type Category struct {
@@ -11,17 +29,31 @@ type Category struct {
 }
 
 type ProductResp struct {
-	ID       int      `json:"id"`
-	Name     string   `json:"name"`
-	Price    float64  `json:"price"`
-	Stock    int      `json:"stock"`
-	Category Category `json:"category"`
+	ID          int          `json:"id"`
+	Name        string       `json:"name"`
+	Price       models.Money `json:"price" swaggertype:"number"`
+	Stock       int          `json:"stock"`
+	CostPrice   models.Money `json:"cost_price" swaggertype:"number"`
+	MinStock    int          `json:"min_stock"`
+	ReorderQty  int          `json:"reorder_qty"`
+	TaxRateID   *int         `json:"tax_rate_id"`
+	SKU         string       `json:"sku"`
+	Barcodes    []string     `json:"barcodes"`
+	HasVariants bool         `json:"has_variants"`
+	Category    Category     `json:"category"`
+	DeletedAt   *time.Time   `json:"deleted_at,omitempty"`
+	Version     int          `json:"version"`
 }
 
 type SalesSummary struct {
-	TotalRevenue   int            `json:"total_revenue"`
-	TotalTransaksi int            `json:"total_transaksi"`
-	ProdukTerlaris ProdukTerlaris `json:"produk_terlaris"`
+	TotalRevenue   models.Money                `json:"total_revenue" swaggertype:"number"` // revenue bersih
+	GrossRevenue   models.Money                `json:"gross_revenue" swaggertype:"number"`
+	TotalDiscount  models.Money                `json:"total_discount" swaggertype:"number"`
+	TotalRefund    models.Money                `json:"total_refund" swaggertype:"number"`
+	TotalTransaksi int                         `json:"total_transaksi"`
+	ProdukTerlaris ProdukTerlaris              `json:"produk_terlaris"`
+	Pembayaran     []models.PaymentMethodTotal `json:"pembayaran"`
+	Profit         models.ProfitReport         `json:"profit"` // HPP dan laba kotor per produk dan per kategori
 }
 
 type ProdukTerlaris struct {